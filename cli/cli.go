@@ -0,0 +1,51 @@
+/*
+Package cli provides the command line interface of rtc
+*/
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sebach1/rtc/git"
+	"github.com/sebach1/rtc/schema"
+)
+
+// An Env is the environment the commands are ran on
+type Env struct {
+	DB        *sqlx.DB
+	Project   *schema.Planisphere
+	Community *git.Community
+
+	// Out is where the commands write its output to
+	Out io.Writer
+}
+
+// A Command performs an action given the arguments which follow its name
+type Command func(ctx context.Context, env *Env, args []string) error
+
+var commands = map[string]Command{
+	"plan":  plan,
+	"stash": stash,
+}
+
+// Run performs the command named by the first of the given args
+func Run(ctx context.Context, env *Env, args []string) error {
+	if len(args) == 0 {
+		return errNoCommand
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return errUnknownCommand
+	}
+	return cmd(ctx, env, args[1:])
+}
+
+// encode writes the given value as indented JSON onto the output of the env
+func (env *Env) encode(v interface{}) error {
+	enc := json.NewEncoder(env.Out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
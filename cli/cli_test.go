@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{name: "NO command", wantErr: errNoCommand},
+		{name: "UNKNOWN command", args: []string{"foo"}, wantErr: errUnknownCommand},
+		{name: "plan WITHOUT branch", args: []string{"plan", "-schema", "foo"}, wantErr: errNoBranch},
+		{name: "stash WITHOUT action", args: []string{"stash"}, wantErr: errNoStashAction},
+		{name: "stash UNKNOWN action", args: []string{"stash", "foo", "-branch", "foo"}, wantErr: errUnknownStashAction},
+		{name: "stash WITHOUT branch", args: []string{"stash", "list"}, wantErr: errNoBranch},
+		{name: "stash push WITHOUT name", args: []string{"stash", "push", "-branch", "foo"}, wantErr: errNoStashName},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := Run(context.Background(), &Env{Out: &bytes.Buffer{}}, tt.args)
+			if err != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package cli
+
+import "errors"
+
+var (
+	errNoCommand      = errors.New("COMMAND is NOT GIVEN")
+	errUnknownCommand = errors.New("the COMMAND is UNKNOWN")
+	errNoBranch       = errors.New("BRANCH is NOT GIVEN (-branch)")
+
+	errNoStashAction      = errors.New("STASH ACTION is NOT GIVEN (push, pop, list or drop)")
+	errUnknownStashAction = errors.New("the STASH ACTION is UNKNOWN")
+	errNoStashName        = errors.New("STASH NAME is NOT GIVEN (-name)")
+)
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+
+	"github.com/sebach1/rtc/git"
+	"github.com/sebach1/rtc/integrity"
+)
+
+// plan prints the plan of the orchestration of a branch. See git.PlanOrchestration
+// Usage: plan -branch <branch> [-schema <schema>]
+// Notice without schema the commits of the branch can span many schemas. See git.Owner.Delegate
+func plan(ctx context.Context, env *Env, args []string) error {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	branch := fs.String("branch", "", "name of the branch to plan")
+	sch := fs.String("schema", "", "name of the schema the branch is orchestrated with, if only one")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *branch == "" {
+		return errNoBranch
+	}
+
+	p, err := git.PlanOrchestration(ctx, env.DB, env.Project,
+		integrity.BranchName(*branch), integrity.SchemaName(*sch), env.Community)
+	if err != nil {
+		return err
+	}
+	return env.encode(p)
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+
+	"github.com/sebach1/rtc/git"
+	"github.com/sebach1/rtc/integrity"
+)
+
+// stash parks and re-applies the uncommitted changes of the index of a branch. See git.StashPush
+// Usage: stash <push|pop|list|drop> -branch <branch> [-name <name>]
+// Notice the name is required to push, and pop and drop act on the latest stash without it
+func stash(ctx context.Context, env *Env, args []string) error {
+	if len(args) == 0 {
+		return errNoStashAction
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("stash "+action, flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	branch := fs.String("branch", "", "name of the branch whose index is stashed")
+	name := fs.String("name", "", "name of the stash")
+	err := fs.Parse(args[1:])
+	if err != nil {
+		return err
+	}
+	if *branch == "" {
+		return errNoBranch
+	}
+	branchName := integrity.BranchName(*branch)
+
+	switch action {
+	case "push":
+		if *name == "" {
+			return errNoStashName
+		}
+		st, err := git.StashPush(ctx, env.DB, branchName, *name)
+		if err != nil {
+			return err
+		}
+		return env.encode(st)
+	case "pop":
+		st, overrides, err := git.StashPop(ctx, env.DB, env.Project, branchName, *name)
+		if err != nil {
+			return err
+		}
+		return env.encode(struct {
+			Stash     *git.Stash      `json:"stash,omitempty"`
+			Overrides []*git.Conflict `json:"overrides,omitempty"`
+		}{st, overrides})
+	case "list":
+		stashes, err := git.StashList(ctx, env.DB, branchName)
+		if err != nil {
+			return err
+		}
+		return env.encode(stashes)
+	case "drop":
+		st, err := git.StashDrop(ctx, env.DB, branchName, *name)
+		if err != nil {
+			return err
+		}
+		return env.encode(st)
+	}
+	return errUnknownStashAction
+}
@@ -1,4 +1,15 @@
 package config
 
+import "os"
+
 // DefaultDBSrc is the default database source uri without the scheme
 const DefaultDBSrc = "localhost"
+
+// DataSource retrieves the database source uri from the env, falling back to DefaultDBSrc
+func DataSource() string {
+	dataSource := os.Getenv("db")
+	if dataSource == "" {
+		return DefaultDBSrc
+	}
+	return dataSource
+}
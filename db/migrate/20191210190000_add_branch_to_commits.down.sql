@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS changes_commit_id_idx;
+DROP INDEX IF EXISTS commits_branch_id_idx;
+ALTER TABLE changes DROP COLUMN IF EXISTS commit_id;
+ALTER TABLE changes DROP COLUMN IF EXISTS index_id;
+ALTER TABLE commits DROP COLUMN IF EXISTS branch_id;
+ALTER TABLE commits DROP COLUMN IF EXISTS merged;
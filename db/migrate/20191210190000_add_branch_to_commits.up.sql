@@ -0,0 +1,6 @@
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS merged bool DEFAULT false;
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS branch_id integer;
+ALTER TABLE changes ADD COLUMN IF NOT EXISTS index_id integer;
+ALTER TABLE changes ADD COLUMN IF NOT EXISTS commit_id integer DEFAULT 0;
+CREATE INDEX IF NOT EXISTS commits_branch_id_idx ON commits (branch_id, id);
+CREATE INDEX IF NOT EXISTS changes_commit_id_idx ON changes (commit_id);
@@ -0,0 +1 @@
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS pre_image_id integer;
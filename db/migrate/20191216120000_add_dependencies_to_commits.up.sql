@@ -0,0 +1 @@
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS depends_on integer[];
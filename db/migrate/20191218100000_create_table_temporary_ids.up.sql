@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS temporary_ids (
+   id integer PRIMARY KEY,
+   branch_id integer NOT NULL,
+   placeholder varchar(255) NOT NULL,
+   entity_id varchar(255) NOT NULL,
+   UNIQUE (branch_id, placeholder)
+);
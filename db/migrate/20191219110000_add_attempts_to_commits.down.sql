@@ -0,0 +1 @@
+ALTER TABLE commits DROP COLUMN IF EXISTS attempts;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS results (
+   id integer PRIMARY KEY,
+   commit_id integer NOT NULL,
+   idempotency_key varchar(64) UNIQUE NOT NULL,
+   entity_id varchar(255),
+   attempts integer DEFAULT 0,
+   compensation bool DEFAULT false
+);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS jobs (
+   id integer PRIMARY KEY,
+   branch varchar(30) NOT NULL,
+   schema varchar(255),
+   saga bool DEFAULT false,
+   state varchar(30) NOT NULL,
+   attempts integer DEFAULT 0,
+   error text,
+   leased_until timestamp,
+   pull_request_id integer,
+   created_at timestamp NOT NULL,
+   updated_at timestamp NOT NULL
+);
+CREATE INDEX IF NOT EXISTS jobs_state_idx ON jobs (state, leased_until);
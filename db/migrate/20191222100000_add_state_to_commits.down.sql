@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS transitions;
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS merged bool DEFAULT false;
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS errored bool;
+UPDATE commits SET merged=true WHERE state IN ('merged', 'reverted');
+UPDATE commits SET errored=true WHERE state IN ('rejected', 'failed');
+ALTER TABLE commits DROP COLUMN IF EXISTS state;
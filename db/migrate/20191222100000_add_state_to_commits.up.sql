@@ -0,0 +1,12 @@
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS state varchar(16) NOT NULL DEFAULT 'pending';
+UPDATE commits SET state='merged' WHERE merged=true;
+UPDATE commits SET state='rejected' WHERE merged=false AND errored=true;
+ALTER TABLE commits DROP COLUMN IF EXISTS merged;
+ALTER TABLE commits DROP COLUMN IF EXISTS errored;
+CREATE TABLE IF NOT EXISTS transitions (
+   id integer PRIMARY KEY,
+   commit_id integer NOT NULL,
+   from_state varchar(16) NOT NULL,
+   to_state varchar(16) NOT NULL,
+   at timestamp NOT NULL
+);
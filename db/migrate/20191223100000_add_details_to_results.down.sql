@@ -0,0 +1,13 @@
+ALTER TABLE results DROP COLUMN IF EXISTS finished_at;
+ALTER TABLE results DROP COLUMN IF EXISTS started_at;
+ALTER TABLE results DROP COLUMN IF EXISTS payload;
+ALTER TABLE results DROP COLUMN IF EXISTS collaborator;
+ALTER TABLE results DROP COLUMN IF EXISTS error_message;
+ALTER TABLE results DROP COLUMN IF EXISTS error_class;
+DROP INDEX IF EXISTS results_pull_request_id_idx;
+ALTER TABLE results DROP COLUMN IF EXISTS pull_request_id;
+DROP INDEX IF EXISTS results_idempotency_key_idx;
+DELETE FROM results WHERE idempotency_key IS NULL OR idempotency_key='';
+ALTER TABLE results ALTER COLUMN idempotency_key SET NOT NULL;
+DELETE FROM results r USING results dup WHERE r.idempotency_key=dup.idempotency_key AND r.id>dup.id;
+ALTER TABLE results ADD CONSTRAINT results_idempotency_key_key UNIQUE (idempotency_key);
@@ -0,0 +1,11 @@
+ALTER TABLE results DROP CONSTRAINT IF EXISTS results_idempotency_key_key;
+ALTER TABLE results ALTER COLUMN idempotency_key DROP NOT NULL;
+CREATE INDEX IF NOT EXISTS results_idempotency_key_idx ON results (idempotency_key);
+ALTER TABLE results ADD COLUMN IF NOT EXISTS pull_request_id integer;
+CREATE INDEX IF NOT EXISTS results_pull_request_id_idx ON results (pull_request_id);
+ALTER TABLE results ADD COLUMN IF NOT EXISTS error_class varchar(16) DEFAULT '';
+ALTER TABLE results ADD COLUMN IF NOT EXISTS error_message text DEFAULT '';
+ALTER TABLE results ADD COLUMN IF NOT EXISTS collaborator varchar(255) DEFAULT '';
+ALTER TABLE results ADD COLUMN IF NOT EXISTS payload bytea;
+ALTER TABLE results ADD COLUMN IF NOT EXISTS started_at timestamp;
+ALTER TABLE results ADD COLUMN IF NOT EXISTS finished_at timestamp;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS stashes (
+   id integer PRIMARY KEY,
+   branch_id integer NOT NULL,
+   index_id integer NOT NULL,
+   name varchar(255) NOT NULL,
+   UNIQUE (branch_id, name)
+);
@@ -0,0 +1 @@
+ALTER TABLE branches ADD COLUMN IF NOT EXISTS schema varchar(255) NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS reverts_id integer DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE jobs DROP COLUMN IF EXISTS failed_commit_ids;
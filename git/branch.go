@@ -6,6 +6,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/sebach1/rtc/integrity"
 	"github.com/sebach1/rtc/internal/store"
+	"github.com/sebach1/rtc/schema"
 )
 
 // Branch is the state-manager around indices
@@ -13,6 +14,10 @@ type Branch struct {
 	Id   int64  `json:"id,omitempty"`
 	Name string `json:"name,omitempty"`
 
+	// Schema is the one the branch is bound to, which validates the changes added onto it
+	// If it's empty, the changes are validated against the schema of its table. See Branch.validate
+	Schema integrity.SchemaName `json:"schema,omitempty"`
+
 	Credentials credentials
 
 	Index *Index `json:"index,omitempty"`
@@ -20,22 +25,29 @@ type Branch struct {
 	IndexId int64 `json:"index_id,omitempty"`
 }
 
+// UnmergedCommits retrieves the commits of the branch which didn't finish its lifecycle yet
+// Notice the rejected and failed ones are included, since they can be orchestrated again
 func (b *Branch) UnmergedCommits(ctx context.Context, db *sqlx.DB) ([]*Commit, error) {
-	rows, err := db.NamedQueryContext(ctx, `SELECT * FROM commits WHERE merged=false AND branch_id=:id`, b)
-	if err != nil {
-		return nil, err
+	return b.CommitsByState(ctx, db, Pending, Reviewing, Rejected, Delegated, InFlight, Failed)
+}
+
+// CommitsByState retrieves the commits of the branch which are in any of the given states
+func (b *Branch) CommitsByState(ctx context.Context, db *sqlx.DB, states ...CommitState) ([]*Commit, error) {
+	if len(states) == 0 {
+		return nil, nil
 	}
-	var comms []*Commit
-	defer rows.Close()
-	for rows.Next() {
-		comm := &Commit{}
-		err = rows.StructScan(comm)
+	for _, state := range states {
+		err := state.Validate()
 		if err != nil {
 			return nil, err
 		}
-		comms = append(comms, comm)
 	}
-	err = rows.Err()
+	qr, args, err := sqlx.In(`SELECT * FROM commits WHERE branch_id=? AND state IN (?) ORDER BY id`, b.Id, states)
+	if err != nil {
+		return nil, err
+	}
+	var comms []*Commit
+	err = db.SelectContext(ctx, &comms, qr, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -45,18 +57,24 @@ func (b *Branch) UnmergedCommits(ctx context.Context, db *sqlx.DB) ([]*Commit, e
 // NewBranchWithIndex safety creates a new Branch entity and assigns a new index_id to it
 // Notice it persists on the db and assigns the inserted id
 func NewBranchWithIndex(ctx context.Context, db *sqlx.DB, name integrity.BranchName) (*Branch, error) {
+	return newBranchWithIndex(ctx, db, name, "")
+}
+
+// newBranchWithIndex creates a new Branch bound to the given schema. See NewBranchWithIndex
+func newBranchWithIndex(
+	ctx context.Context,
+	db *sqlx.DB,
+	name integrity.BranchName,
+	schName integrity.SchemaName,
+) (*Branch, error) {
 	if ctx == nil {
 		ctx = context.Background() // Avoid panicking on .ExecContext()
 	}
-	res, err := db.ExecContext(ctx, `INSERT INTO indices DEFAULT VALUES`)
+	idxId, err := insertIndex(ctx, db)
 	if err != nil {
 		return nil, err
 	}
-	idxId, err := res.LastInsertId()
-	if err != nil {
-		return nil, err
-	}
-	branch := &Branch{Name: string(name), IndexId: idxId}
+	branch := &Branch{Name: string(name), Schema: schName, IndexId: idxId}
 	err = store.InsertIntoDB(ctx, db, branch)
 	if err != nil {
 		return nil, err
@@ -64,6 +82,38 @@ func NewBranchWithIndex(ctx context.Context, db *sqlx.DB, name integrity.BranchN
 	return branch, nil
 }
 
+// validate checks the change against the schema the branch is bound to (or the given schName, if it isn't)
+// In case neither of them are given, the schema of its table is looked up along the project
+// Notice without project only the structure of the change is validated. See Change.Validate
+func (b *Branch) validate(project *schema.Planisphere, schName integrity.SchemaName, chg *Change) error {
+	if b.Schema != "" && schName != "" && b.Schema != schName {
+		return errForeignSchema
+	}
+	if b.Schema != "" {
+		schName = b.Schema
+	}
+	if project == nil {
+		return chg.Validate()
+	}
+	var sch *schema.Schema
+	var schErr error
+	if schName != "" {
+		sch, schErr = project.GetSchemaFromName(schName)
+	} else {
+		sch, schErr = project.SchemaOfTable(chg.TableName)
+	}
+	return validateChange(project, sch, schErr, chg)
+}
+
+// insertIndex persists a new empty index, retrieving its id
+func insertIndex(ctx context.Context, db sqlx.ExtContext) (int64, error) {
+	res, err := db.ExecContext(ctx, `INSERT INTO indices DEFAULT VALUES`)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
 // FetchIndex retrieves the Index by .IndexId and assigns it to .Index field
 func (b *Branch) FetchIndex(ctx context.Context, db *sqlx.DB) error {
 	if b.IndexId == 0 {
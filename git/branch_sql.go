@@ -26,6 +26,7 @@ func (b *Branch) SQLColumns() []string {
 	return []string{
 		"id",
 		"name",
+		"schema",
 		"index_id",
 	}
 }
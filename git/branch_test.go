@@ -12,6 +12,7 @@ import (
 	"github.com/sebach1/rtc/integrity"
 	"github.com/sebach1/rtc/internal/test/assist"
 	"github.com/sebach1/rtc/internal/test/thelper"
+	"github.com/sebach1/rtc/schema"
 )
 
 var (
@@ -174,3 +175,127 @@ func (b *Branch) rmIndexChangesAndReturn() *Branch {
 	b.Index.Changes = nil
 	return b
 }
+
+func TestBranch_validate(t *testing.T) {
+	t.Parallel()
+	project := &schema.Planisphere{gSchemas.Foo, gSchemas.Bar}
+	tests := []struct {
+		name    string
+		branch  *Branch
+		project *schema.Planisphere
+		schName integrity.SchemaName
+		chg     *Change
+		wantErr string
+	}{
+		{
+			name:   "WITHOUT project only the structure is validated",
+			branch: &Branch{Schema: gSchemas.Foo.Name},
+			chg:    gChanges.Bar.Update.copy(t),
+		},
+		{
+			name:    "change of the BOUND schema",
+			branch:  &Branch{Schema: gSchemas.Foo.Name},
+			project: project,
+			chg:     gChanges.Foo.Update.copy(t),
+		},
+		{
+			name:    "change of ANOTHER schema than the BOUND one",
+			branch:  &Branch{Schema: gSchemas.Foo.Name},
+			project: project,
+			chg:     gChanges.Bar.Update.copy(t),
+			wantErr: "the TABLE given does NOT BELONGS to the given SCHEMA",
+		},
+		{
+			name:    "given schema DIFFERS from the BOUND one",
+			branch:  &Branch{Schema: gSchemas.Foo.Name},
+			project: project,
+			schName: gSchemas.Bar.Name,
+			chg:     gChanges.Bar.Update.copy(t),
+			wantErr: errForeignSchema.Error(),
+		},
+		{
+			name:    "change of ANOTHER schema than the GIVEN one",
+			branch:  &Branch{},
+			project: project,
+			schName: gSchemas.Foo.Name,
+			chg:     gChanges.Bar.Update.copy(t),
+			wantErr: "the TABLE given does NOT BELONGS to the given SCHEMA",
+		},
+		{
+			name:    "UNBOUND branch validates against the schema of the table",
+			branch:  &Branch{},
+			project: project,
+			chg:     gChanges.Bar.Update.copy(t),
+		},
+		{
+			name:    "UNBOUND branch but table NOT IN PROJECT",
+			branch:  &Branch{},
+			project: &schema.Planisphere{gSchemas.Foo},
+			chg:     gChanges.Bar.Update.copy(t),
+			wantErr: "the TABLE given does NOT EXISTS",
+		},
+		{
+			name:    "BOUND schema NOT IN PROJECT",
+			branch:  &Branch{Schema: gSchemas.Bar.Name},
+			project: &schema.Planisphere{gSchemas.Foo},
+			chg:     gChanges.Foo.Update.copy(t),
+			wantErr: "the given SCHEMA NAME is NOT FOUND in scope",
+		},
+		{
+			name:    "option key of ANOTHER table",
+			branch:  &Branch{Schema: gSchemas.Foo.Name},
+			project: project,
+			chg:     gChanges.Foo.Options.copy(t),
+			wantErr: "the provided OPTION KEY is INVALId OR NOT DEFINED",
+		},
+		{
+			name:    "column of ANOTHER table of the schema",
+			branch:  &Branch{Schema: gSchemas.FooBar.Name},
+			project: &schema.Planisphere{gSchemas.FooBar},
+			chg:     gChanges.Foo.ColumnName.copy(t),
+			wantErr: "the COLUMNS given does NOT BELONGS to the given TABLE",
+		},
+		{
+			name:    "column NOT IN SCHEMA",
+			branch:  &Branch{Schema: gSchemas.Foo.Name},
+			project: project,
+			chg:     gChanges.Foo.ColumnName.copy(t),
+			wantErr: "the COLUMN given does NOT EXISTS",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.branch.validate(tt.project, tt.schName, tt.chg)
+			if errMsg(err) != tt.wantErr {
+				t.Errorf("Branch.validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAdd_newBranch(t *testing.T) {
+	project := &schema.Planisphere{gSchemas.Foo, gSchemas.Bar}
+	chg := gChanges.Bar.Update
+	db, mock := thelper.MockDB(t)
+	(&assist.QueryStubber{Expect: "SELECT * FROM branches WHERE name=?", Rows: sqlmock.NewRows([]string{"id"})}).Stub(mock)
+
+	_, err := Add(context.Background(), db, project, chg.EntityId, chg.TableName, chg.ColumnName,
+		"newBranchName", gSchemas.Foo.Name, chg.Value(), chg.Type, chg.Options)
+	wantErr := "the TABLE given does NOT BELONGS to the given SCHEMA"
+	if errMsg(err) != wantErr {
+		t.Errorf("Add() error = %v, wantErr %v", err, wantErr)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // No branch is created
+		t.Errorf("Add() unfulfilled expectations: %v", err)
+	}
+}
+
+// errMsg retrieves the message of the cause of the error, if any
+func errMsg(err error) string {
+	if err == nil {
+		return ""
+	}
+	return errors.Cause(err).Error()
+}
@@ -0,0 +1,163 @@
+package git
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// A BreakerState is a state of a CircuitBreaker
+type BreakerState string
+
+const (
+	// BreakerClosed lets every delivery through
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen fails every delivery fast, until its .OpenTimeout elapses
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a few trial deliveries through, which decide if it's closed or opened again
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// Defaults of the thresholds of the circuit breakers
+const (
+	defaultFailureThreshold   = 5
+	defaultOpenTimeout        = 30 * time.Second
+	defaultHalfOpenDeliveries = 1
+)
+
+// A CircuitBreaker stops delivering commits to a member whose service is down, in order to
+// fail fast instead of waiting for the timeout of each of them. See Member.Fallback
+// Only the failures which tell the service is unavailable (i.e. the retryable ones) open the circuit
+// Notice it keeps its state between orchestrations, so it must be shared by pointer
+type CircuitBreaker struct {
+	// FailureThreshold is the qt of consecutive failed deliveries which opens the circuit
+	FailureThreshold int `json:"failure_threshold,omitempty"`
+	// OpenTimeout is the time the circuit stays open before letting the trial deliveries through
+	OpenTimeout time.Duration `json:"open_timeout,omitempty"`
+	// HalfOpenDeliveries is the qt of trial deliveries, which must all succeed in order to close the circuit
+	HalfOpenDeliveries int `json:"half_open_deliveries,omitempty"`
+
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	openedAt  time.Time
+	trials    int // Trial deliveries let through while half-open
+	successes int // Trial deliveries succeeded while half-open
+}
+
+func (cb *CircuitBreaker) failureThreshold() int {
+	if cb.FailureThreshold < 1 {
+		return defaultFailureThreshold
+	}
+	return cb.FailureThreshold
+}
+
+func (cb *CircuitBreaker) openTimeout() time.Duration {
+	if cb.OpenTimeout <= 0 {
+		return defaultOpenTimeout
+	}
+	return cb.OpenTimeout
+}
+
+func (cb *CircuitBreaker) halfOpenDeliveries() int {
+	if cb.HalfOpenDeliveries < 1 {
+		return defaultHalfOpenDeliveries
+	}
+	return cb.HalfOpenDeliveries
+}
+
+// current retrieves the state of the circuit, half-opening it once its open timeout elapsed
+// It must be called with the lock held
+func (cb *CircuitBreaker) current(now time.Time) BreakerState {
+	if cb.state == "" {
+		cb.state = BreakerClosed
+	}
+	if cb.state == BreakerOpen && now.Sub(cb.openedAt) >= cb.openTimeout() {
+		cb.state, cb.trials, cb.successes = BreakerHalfOpen, 0, 0
+	}
+	return cb.state
+}
+
+// State retrieves the current state of the circuit
+// A nil circuit breaker is always closed
+func (cb *CircuitBreaker) State() BreakerState {
+	if cb == nil {
+		return BreakerClosed
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.current(time.Now())
+}
+
+// allow tells if a delivery can be performed, taking a trial one in case it's half-open
+func (cb *CircuitBreaker) allow() bool {
+	if cb == nil {
+		return true
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.current(time.Now()) {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if cb.trials >= cb.halfOpenDeliveries() {
+			return false
+		}
+		cb.trials++
+	}
+	return true
+}
+
+// record takes the outcome of an allowed delivery
+func (cb *CircuitBreaker) record(err error) {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	now := time.Now()
+	if errors.Cause(err) == context.Canceled { // Tells nothing about the service
+		if cb.current(now) == BreakerHalfOpen {
+			cb.trials--
+		}
+		return
+	}
+	failed := err != nil && IsRetryable(err)
+	switch cb.current(now) {
+	case BreakerClosed:
+		if !failed {
+			cb.failures = 0
+			return
+		}
+		cb.failures++
+		if cb.failures >= cb.failureThreshold() {
+			cb.open(now)
+		}
+	case BreakerHalfOpen:
+		if failed {
+			cb.open(now)
+			return
+		}
+		cb.successes++
+		if cb.successes >= cb.halfOpenDeliveries() {
+			cb.state, cb.failures = BreakerClosed, 0
+		}
+	}
+}
+
+// open opens the circuit. It must be called with the lock held
+func (cb *CircuitBreaker) open(now time.Time) {
+	cb.state, cb.openedAt, cb.failures = BreakerOpen, now, 0
+}
+
+// do performs the delivery through the circuit, failing fast with errCircuitOpen if it's open
+func (cb *CircuitBreaker) do(deliver func() error) error {
+	if !cb.allow() {
+		return errCircuitOpen
+	}
+	err := deliver()
+	cb.record(err)
+	return err
+}
@@ -0,0 +1,112 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/schema"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+	cb := &CircuitBreaker{FailureThreshold: 2, OpenTimeout: 50 * time.Millisecond, HalfOpenDeliveries: 1}
+	deliver := func(err error) func() error {
+		return func() error { return err }
+	}
+	steps := []struct {
+		name      string
+		sleep     time.Duration
+		err       error
+		wantErr   error
+		wantState BreakerState
+	}{
+		{name: "PERMANENT failure doesn't count", err: errFoo, wantErr: errFoo, wantState: BreakerClosed},
+		{name: "first TRANSIENT failure", err: retryableErr(true), wantErr: retryableErr(true), wantState: BreakerClosed},
+		{name: "THRESHOLD reached", err: retryableErr(true), wantErr: retryableErr(true), wantState: BreakerOpen},
+		{name: "OPEN fails fast", wantErr: errCircuitOpen, wantState: BreakerOpen},
+		{name: "HALF-OPEN trial fails", sleep: 60 * time.Millisecond, err: retryableErr(true), wantErr: retryableErr(true), wantState: BreakerOpen},
+		{name: "HALF-OPEN canceled trial", sleep: 60 * time.Millisecond, err: context.Canceled, wantErr: context.Canceled, wantState: BreakerHalfOpen},
+		{name: "HALF-OPEN trial succeeds", wantState: BreakerClosed},
+	}
+	for _, step := range steps { // Sequential, as each step depends on the previous ones
+		time.Sleep(step.sleep)
+		if err := cb.do(deliver(step.err)); err != step.wantErr {
+			t.Errorf("%v: CircuitBreaker.do() error = %v, wantErr %v", step.name, err, step.wantErr)
+		}
+		if got := cb.State(); got != step.wantState {
+			t.Errorf("%v: CircuitBreaker.State() = %v, want %v", step.name, got, step.wantState)
+		}
+	}
+
+	var nilCb *CircuitBreaker
+	if err := nilCb.do(deliver(nil)); err != nil || nilCb.State() != BreakerClosed {
+		t.Errorf("NIL CircuitBreaker is NOT CLOSED")
+	}
+}
+
+func TestTeam_Delegate_breaker(t *testing.T) {
+	t.Parallel()
+	open := func() *CircuitBreaker {
+		cb := &CircuitBreaker{FailureThreshold: 1, OpenTimeout: time.Hour}
+		cb.record(retryableErr(true))
+		return cb
+	}
+	fallback := &collabMock{}
+	tests := []struct {
+		name     string
+		breaker  *CircuitBreaker
+		fallback Collaborator
+		want     Collaborator
+		wantErr  error
+	}{
+		{name: "CLOSED circuit delegates to the member", breaker: &CircuitBreaker{}, fallback: fallback},
+		{name: "OPEN circuit delegates to the FALLBACK", breaker: open(), fallback: fallback, want: fallback},
+		{name: "OPEN circuit WITHOUT fallback fails fast", breaker: open(), wantErr: errCircuitOpen},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			team := gTeams.ZeroMembers.copy(t).mock(gTables.Foo.Name, nil)
+			team.Members[0].Breaker, team.Members[0].Fallback = tt.breaker, tt.fallback
+			want := tt.want
+			if want == nil && tt.wantErr == nil {
+				want = team.Members[0].Collab
+			}
+			got, err := team.Delegate(gTables.Foo.Name)
+			if err != tt.wantErr {
+				t.Fatalf("Team.Delegate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != want {
+				t.Errorf("Team.Delegate() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestOwner_Orchestrate_breaker(t *testing.T) {
+	t.Parallel()
+	team := gTeams.Foo.copy(t).mock(gTables.Foo.Name, retryableErr(true))
+	team.Members[0].Breaker = &CircuitBreaker{FailureThreshold: 1, OpenTimeout: time.Hour}
+
+	var classes []ErrorClass
+	for i := 0; i < 2; i++ { // The first orchestration opens the circuit
+		pR := &PullRequest{Commits: []*Commit{{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}}}}
+		own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+		own.Waiter.Add(1)
+		go own.Orchestrate(context.Background(), &Community{team}, gSchemas.Foo.Name, pR)
+		err := own.WaitAndClose()
+		if err != nil {
+			t.Fatalf("Owner.Orchestrate() error = %v", err)
+		}
+		for res := range own.Summary {
+			res.describe()
+			classes = append(classes, res.ErrorClass)
+		}
+	}
+	if diff := cmp.Diff([]ErrorClass{ErrorClassTransient, ErrorClassCircuitOpen}, classes); diff != "" {
+		t.Errorf("Owner.Orchestrate() error classes mismatch (-want +got): %s", diff)
+	}
+}
@@ -0,0 +1,32 @@
+package git
+
+import (
+	"context"
+	"time"
+)
+
+// detached is a context which keeps the values of its parent, but neither its deadline nor its cancellation
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (detached) Done() <-chan struct{} { return nil }
+
+func (detached) Err() error { return nil }
+
+// detach retrieves the ctx without its cancellation
+// It's used to record the outcome of the actions which were already performed, even when the
+// orchestration was canceled or timed out meanwhile
+func detach(ctx context.Context) context.Context {
+	return detached{ctx}
+}
+
+// withTimeout bounds the ctx by the timeout of the commit delivery, if any. See Delegation
+func (comm *Commit) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if comm.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, comm.Timeout)
+}
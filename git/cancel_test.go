@@ -0,0 +1,111 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/schema"
+)
+
+func TestOwner_Orchestrate_cancellation(t *testing.T) {
+	t.Parallel()
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		timeout    time.Duration
+		saga       bool
+		wantStates []CommitState
+	}{
+		{
+			name:       "TIMED OUT delivery",
+			ctx:        context.Background(),
+			timeout:    time.Millisecond,
+			wantStates: []CommitState{Failed, Failed},
+		},
+		{
+			name:       "CANCELED orchestration doesn't start the commits",
+			ctx:        canceled,
+			wantStates: []CommitState{Failed, Failed},
+		},
+		{
+			name:       "CANCELED saga doesn't start the commits",
+			ctx:        canceled,
+			saga:       true,
+			wantStates: []CommitState{Failed, Failed},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			team := gTeams.Foo.copy(t)
+			err := team.AddMember(gTables.Foo.Name, &blockingMock{}, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			team.Timeout = tt.timeout
+			pR := &PullRequest{Commits: []*Commit{
+				{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}},
+				{Id: 2, Changes: []*Change{gChanges.Foo.Create.copy(t)}, DependsOn: []int64{1}},
+			}}
+			own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+			own.Saga = tt.saga
+			own.Waiter.Add(1)
+			go own.Orchestrate(tt.ctx, &Community{team}, gSchemas.Foo.Name, pR)
+
+			done := make(chan error)
+			go func() { done <- own.WaitAndClose() }()
+			select {
+			case err = <-done:
+			case <-time.After(time.Second):
+				t.Fatalf("Owner.WaitAndClose() HANGS")
+			}
+			if err != nil {
+				t.Fatalf("Owner.Orchestrate() error = %v", err)
+			}
+
+			var gotStates []CommitState
+			for _, comm := range pR.Commits {
+				gotStates = append(gotStates, comm.State)
+			}
+			if diff := cmp.Diff(tt.wantStates, gotStates); diff != "" {
+				t.Errorf("Owner.Orchestrate() states mismatch (-want +got): %s", diff)
+			}
+			var qtResults int
+			for res := range own.Summary {
+				qtResults++
+				if res.Error == nil {
+					t.Errorf("Owner.Orchestrate() result of commit %v WITHOUT error", res.CommitId)
+				}
+			}
+			if qtResults != len(pR.Commits) {
+				t.Errorf("Owner.Orchestrate() qt of results = %v, want %v", qtResults, len(pR.Commits))
+			}
+		})
+	}
+}
+
+func TestOwner_report(t *testing.T) {
+	t.Parallel()
+	own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+	own.report(&Result{CommitId: 1}) // Before the summary was opened
+	own.Summary = make(chan *Result, 1)
+	own.report(&Result{CommitId: 2})
+	own.report(&Result{CommitId: 3}) // Overflows the summary
+
+	err := own.WaitAndClose()
+	if err != nil {
+		t.Fatalf("Owner.WaitAndClose() error = %v", err)
+	}
+	var got []int64
+	for res := range own.Summary {
+		got = append(got, res.CommitId)
+	}
+	if diff := cmp.Diff([]int64{2, 1, 3}, got); diff != "" {
+		t.Errorf("Owner.report() results mismatch (-want +got): %s", diff)
+	}
+}
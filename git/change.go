@@ -1,6 +1,8 @@
 package git
 
 import (
+	"bytes"
+
 	"github.com/sebach1/rtc/integrity"
 )
 
@@ -40,12 +42,14 @@ func NewChange(
 	opts Options,
 ) (*Change, error) {
 	chg := &Change{EntityId: entityId, TableName: tableName, ColumnName: columnName, Type: Type, Options: opts}
-	err := chg.SetValue(val)
-	if err != nil {
-		return nil, err
+	if val != nil { // Retrievals and deletions are valueless
+		err := chg.SetValue(val)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	err = chg.Validate()
+	err := chg.Validate()
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +144,7 @@ func (chg *Change) Equals(otherChg *Change) bool {
 	if chg.ColumnName != otherChg.ColumnName {
 		return false
 	}
-	if chg.Value() != otherChg.Value() {
+	if !chg.sameValue(otherChg) {
 		return false
 	}
 	if len(chg.Options) != len(otherChg.Options) {
@@ -155,6 +159,18 @@ func (chg *Change) Equals(otherChg *Change) bool {
 	return true
 }
 
+// sameValue checks if the change sets the same value as the given one
+// Notice the json and bytes values are compared by its content, as slices aren't comparable
+func (chg *Change) sameValue(otherChg *Change) bool {
+	val, otherVal := chg.Value(), otherChg.Value()
+	bytesVal, isBytes := val.([]byte)
+	otherBytesVal, otherIsBytes := otherVal.([]byte)
+	if isBytes || otherIsBytes {
+		return isBytes && otherIsBytes && bytes.Equal(bytesVal, otherBytesVal)
+	}
+	return val == otherVal
+}
+
 // Validate self, wrapping up type validations and table assertion
 func (chg *Change) Validate() (err error) {
 	if chg.TableName == "" {
@@ -205,7 +221,7 @@ func (chg *Change) ToMap() map[string]interface{} {
 	if chg.ColumnName != "" {
 		chgMap[string(chg.ColumnName)] = chg.Value()
 	}
-	if !chg.EntityId.IsNil() {
+	if !chg.EntityId.IsNil() && !chg.EntityId.IsTemporary() { // Placeholders are unknown by the collaborators
 		chgMap["id"] = chg.EntityId
 	}
 	return chgMap
@@ -250,8 +266,11 @@ func (chg *Change) classifyType() (integrity.CRUD, error) {
 	return "", errUnclassifiableChg
 }
 
+// validateCreate checks the change describes a creation
+// Notice the entity could be identified by a temporary id (see integrity.Id.IsTemporary), in order
+// to let other changes reference it before it's created
 func (chg *Change) validateCreate() error {
-	if !chg.EntityId.IsNil() {
+	if !chg.EntityId.IsNil() && !chg.EntityId.IsTemporary() {
 		return errNotNilEntityId
 	}
 	if chg.ColumnName == "" {
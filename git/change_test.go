@@ -126,6 +126,8 @@ func TestChange_Value(t *testing.T) {
 
 func TestChange_classifyType(t *testing.T) {
 	t.Parallel()
+	tmpCreate := gChanges.Foo.Create.copy(t)
+	tmpCreate.EntityId = "tmp:foo"
 	tests := []struct {
 		name    string
 		chg     *Change
@@ -138,6 +140,12 @@ func TestChange_classifyType(t *testing.T) {
 			want:    "create",
 			wantErr: nil,
 		},
+		{
+			name:    "CREATE of a TEMPORARY entity",
+			chg:     tmpCreate,
+			want:    "create",
+			wantErr: nil,
+		},
 		{
 			name:    "correctly typed RETRIEVE",
 			chg:     gChanges.Foo.Retrieve,
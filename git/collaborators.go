@@ -1,6 +1,11 @@
 package git
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
 
 // Collaborator is any agent which performs transactions
 type Collaborator interface {
@@ -14,3 +19,37 @@ type Collaborator interface {
 
 	Delete(context.Context, *Commit) (*Commit, error)
 }
+
+// collaboratorName retrieves the name which identifies the collaborator, which is the name of its type
+func collaboratorName(collab Collaborator) string {
+	if collab == nil {
+		return ""
+	}
+	return fmt.Sprintf("%T", collab)
+}
+
+// A Preparer is a Collaborator which is able to take part of a two-phase commit
+// When all the reviewers of a PullRequest are Preparers, the Owner prepares all of
+// them before committing any, and aborts all of them if any preparation fails
+// Notice the commit given to CommitPrepared and Abort is the previously prepared one
+type Preparer interface {
+	Prepare(context.Context, *Commit) error
+
+	CommitPrepared(context.Context, *Commit) (*Commit, error)
+
+	Abort(context.Context, *Commit) error
+}
+
+// A Renderer is a Collaborator which is able to describe the request it would perform
+// for a commit, without performing it. See Owner.Plan
+type Renderer interface {
+	Render(context.Context, *Commit) (*Request, error)
+}
+
+// A Request is the description of a call to a remote service
+type Request struct {
+	Method string          `json:"method,omitempty"`
+	URL    string          `json:"url,omitempty"`
+	Header http.Header     `json:"header,omitempty"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
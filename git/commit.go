@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"io"
 	"reflect"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/sebach1/rtc/integrity"
 	"github.com/sebach1/rtc/msh"
 )
@@ -20,9 +22,45 @@ type Commit struct {
 
 	BranchId int64 `json:"branch_id,omitempty"`
 
-	Merged bool `json:"merged,omitempty"`
+	// DependsOn are the ids of the commits which must be merged before this one. See CommitRef
+	DependsOn pq.Int64Array `json:"depends_on,omitempty"`
 
-	Errored bool `json:"errored,omitempty"`
+	// State is the current stage of the lifecycle of the commit, and Transitions its history
+	State       CommitState   `json:"state,omitempty"`
+	Transitions []*Transition `json:"transitions,omitempty"`
+
+	// PreImage is the state of the entity before the commit was merged. See Commit.Inverse
+	PreImage   *Commit `json:"pre_image,omitempty"`
+	PreImageId int64   `json:"pre_image_id,omitempty"`
+
+	// RevertsId is the id of the merged commit it undoes, which becomes Reverted once it's merged. See Revert
+	RevertsId int64 `json:"reverts_id,omitempty"`
+
+	// Delivery is the not persisted settings it's delivered with. See Commit.absorb
+	Delivery `json:"-"`
+	// Attempts is the qt of calls performed to the reviewer on its last delivery
+	Attempts int `json:"attempts,omitempty"`
+}
+
+// Delivery groups the settings a commit is delivered with, which are assigned on its review or
+// orchestration, and aren't persisted
+type Delivery struct {
+	// Compensate is the action which undoes the commit when a saga fails. See Owner.Saga
+	// If it's nil, the commit is compensated by its inverse
+	Compensate Compensation
+
+	// RetryPolicy is the policy of the reviewer, assigned on the review. See Delegation
+	RetryPolicy *RetryPolicy
+	// Timeout limits its delivery to the reviewer, assigned on the review. See Delegation
+	Timeout time.Duration
+	// MaxConcurrency caps the concurrent deliveries to its reviewer. See Delegation
+	MaxConcurrency int
+	// RateLimits throttle the calls to its reviewer. See Delegation
+	RateLimits []*RateLimit
+	// Breaker is the circuit breaker of the member it's delegated to. See Member.Breaker
+	Breaker *CircuitBreaker
+	// Failover are the delegations its delivery fails over to, once the reviewer fails. See Team.Strategy
+	Failover []*Delegation
 }
 
 func NewCommit(changes []*Change) *Commit {
@@ -46,6 +84,52 @@ func (comm *Commit) Branch(ctx context.Context, db *sqlx.DB) (*Branch, error) {
 	return b, nil
 }
 
+// absorb takes the result of a collaborator action over the commit
+// Notice it preserves the identity and orchestration data of the commit, and
+// it normalizes the returned changes, which are usually decoded from its map version
+func (comm *Commit) absorb(newComm *Commit) {
+	if newComm == nil || newComm == comm {
+		return
+	}
+	ref := *comm
+	*comm = *newComm
+	comm.Id = ref.Id
+	comm.BranchId = ref.BranchId
+	comm.DependsOn = ref.DependsOn
+	comm.Reviewer = ref.Reviewer
+	comm.State = ref.State
+	comm.Transitions = ref.Transitions
+	comm.PreImage = ref.PreImage
+	comm.PreImageId = ref.PreImageId
+	comm.RevertsId = ref.RevertsId
+	comm.Delivery = ref.Delivery
+	comm.Attempts = ref.Attempts
+
+	if len(ref.Changes) == 0 {
+		return
+	}
+	base := ref.Changes[0]
+	for _, chg := range comm.Changes {
+		if chg.TableName == "" {
+			chg.TableName = base.TableName
+		}
+		if chg.Options == nil {
+			chg.Options = base.Options
+		}
+		if chg.Type == "" {
+			chg.Type = base.Type
+		}
+		if chg.EntityId.IsNil() {
+			chg.EntityId = base.EntityId
+		}
+		chg.IndexId = base.IndexId
+		chg.CommitId = base.CommitId
+		if refChg := ref.changeByColumn(chg.ColumnName); refChg != nil && chg.Id == 0 {
+			chg.Id = refChg.Id
+		}
+	}
+}
+
 // FetchChanges retrieves the changes from DB by its .ChangeIds and assigns them to .Changes field
 func (comm *Commit) FetchChanges(ctx context.Context, db *sqlx.DB) (err error) {
 	rows, err := db.NamedQueryContext(ctx, `SELECT * FROM changes WHERE commit_id=:id`, comm)
@@ -55,7 +139,7 @@ func (comm *Commit) FetchChanges(ctx context.Context, db *sqlx.DB) (err error) {
 	defer rows.Close()
 	for rows.Next() {
 		chg := Change{}
-		err = rows.StructScan(chg)
+		err = rows.StructScan(&chg)
 		if err != nil {
 			return
 		}
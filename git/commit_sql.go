@@ -25,8 +25,11 @@ func (comm *Commit) SQLTable() string {
 func (comm *Commit) SQLColumns() []string {
 	return []string{
 		"id",
-		"errored",
-		"merged",
+		"state",
 		"branch_id",
+		"pre_image_id",
+		"reverts_id",
+		"depends_on",
+		"attempts",
 	}
 }
@@ -12,7 +12,7 @@ import (
 
 func TestCommitSQLColumns(t *testing.T) {
 	comm := Commit{}
-	exclusions := []string{"Reviewer", "Changes"}
+	exclusions := []string{"Reviewer", "Changes", "PreImage", "Delivery", "Transitions"}
 	typeOf := reflect.TypeOf(comm)
 	var want []string
 	for i := 0; i < typeOf.NumField(); i++ {
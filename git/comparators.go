@@ -12,8 +12,9 @@ func AreCompatible(chg, otherChg *Change) bool {
 	if chg.EntityId != otherChg.EntityId {
 		return false
 	}
-	if chg.Type == "create" { // In case of both of EntityIds are nil
+	if chg.Type == "create" && !chg.EntityId.IsTemporary() { // In case of both of EntityIds are nil
 		//  (see that the above comparison discards 2x checking)
+		// Notice the creations of the same temporary entity are joint
 		return false
 	}
 	if chg.Type != otherChg.Type {
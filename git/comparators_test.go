@@ -13,6 +13,8 @@ func TestAreCompatible(t *testing.T) {
 		chg      *Change
 		otherChg *Change
 	}
+	tmpCreate := gChanges.Foo.Create.copy(t)
+	tmpCreate.EntityId = "tmp:foo"
 	tests := []struct {
 		name string
 		args args
@@ -50,6 +52,14 @@ func TestAreCompatible(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "SAME TEMPORARY ENTITIES and SAME TABLE",
+			args: args{
+				chg:      tmpCreate,
+				otherChg: tmpCreate.copy(t),
+			},
+			want: true,
+		},
 		{
 			name: "but diff TYPE",
 			args: args{
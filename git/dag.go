@@ -0,0 +1,242 @@
+package git
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sebach1/rtc/integrity"
+)
+
+// commitRefPrefix is the prefix of the ids which references the entity returned by a create commit
+const commitRefPrefix = "commit:"
+
+// CommitRef returns the placeholder of the entity id that the create commit with the given id
+// will return once merged
+// It can be used as the EntityId or value of a change, and it makes its commit depend on the referenced one
+func CommitRef(commitId int64) integrity.Id {
+	return integrity.Id(commitRefPrefix + strconv.FormatInt(commitId, 10))
+}
+
+// commitIdFromRef retrieves the id of the commit referenced by the given id. See CommitRef
+func commitIdFromRef(id integrity.Id) (int64, bool) {
+	if !strings.HasPrefix(string(id), commitRefPrefix) {
+		return 0, false
+	}
+	commId, err := strconv.ParseInt(strings.TrimPrefix(string(id), commitRefPrefix), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return commId, true
+}
+
+// refs retrieves the ids of the commits referenced by the changes of the commit. See CommitRef
+func (comm *Commit) refs() (ids []int64) {
+	for _, chg := range comm.Changes {
+		if commId, ok := commitIdFromRef(chg.EntityId); ok {
+			ids = append(ids, commId)
+		}
+		if chg.ValueType != "string" {
+			continue
+		}
+		if commId, ok := commitIdFromRef(integrity.Id(chg.StringValue)); ok {
+			ids = append(ids, commId)
+		}
+	}
+	return
+}
+
+// resolveRefs substitutes the references to the given commits (see CommitRef), and the temporary ids
+// they create, by the entity ids they returned
+func (comm *Commit) resolveRefs(deps []*Commit, placeholders map[*Commit]integrity.Id) {
+	ids := make(map[integrity.Id]integrity.Id, len(deps))
+	for _, dep := range deps {
+		if dep.Id != 0 {
+			ids[CommitRef(dep.Id)] = dep.entityId()
+		}
+		if placeholder, ok := placeholders[dep]; ok {
+			ids[placeholder] = dep.entityId()
+		}
+	}
+	for _, chg := range comm.Changes {
+		chg.resolveTemporaryIds(ids)
+	}
+}
+
+// dependencyGraph relates each commit with the commits it depends on
+type dependencyGraph map[*Commit][]*Commit
+
+// dependencies builds the dependency graph of the commits of the pull request
+// A commit depends on the ones it declares in .DependsOn, on the ones it references (see CommitRef)
+// and on the ones which create the temporary entities it uses
+// Notice that references MUST point to create commits
+func (pR *PullRequest) dependencies() (dependencyGraph, error) {
+	byId := make(map[int64]*Commit, len(pR.Commits))
+	for _, comm := range pR.Commits {
+		if comm.Id != 0 {
+			byId[comm.Id] = comm
+		}
+	}
+	creators := make(map[integrity.Id]*Commit)
+	for comm, placeholder := range pR.placeholders() {
+		creators[placeholder] = comm
+	}
+
+	graph := make(dependencyGraph, len(pR.Commits))
+	for _, comm := range pR.Commits {
+		graph[comm] = nil
+		seen := make(map[*Commit]bool)
+		depend := func(dep *Commit) {
+			if !seen[dep] && dep != comm {
+				graph[comm] = append(graph[comm], dep)
+				seen[dep] = true
+			}
+		}
+		for _, depId := range comm.DependsOn {
+			dep, ok := byId[depId]
+			if !ok {
+				return nil, errUnknownDependency
+			}
+			depend(dep)
+		}
+		for _, depId := range comm.refs() {
+			dep, ok := byId[depId]
+			if !ok {
+				return nil, errUnknownDependency
+			}
+			if depType, _ := dep.Type(); depType != "create" {
+				return nil, errInvalidReference
+			}
+			depend(dep)
+		}
+		for _, tmpId := range comm.temporaryRefs() {
+			dep, ok := creators[tmpId]
+			if !ok {
+				return nil, errUnresolvedTemporaryId
+			}
+			depend(dep)
+		}
+	}
+	return graph, nil
+}
+
+// order retrieves the commits of the pull request topologically sorted by its dependencies,
+// preserving the original order between independent commits
+// It returns an error if the dependencies are cyclic
+func (pR *PullRequest) order(graph dependencyGraph) ([]*Commit, error) {
+	pending := make(map[*Commit]int, len(pR.Commits)) // Qt of unsorted dependencies
+	dependents := make(map[*Commit][]*Commit)
+	for _, comm := range pR.Commits {
+		pending[comm] = len(graph[comm])
+		for _, dep := range graph[comm] {
+			dependents[dep] = append(dependents[dep], comm)
+		}
+	}
+
+	var sorted []*Commit
+	sortedSet := make(map[*Commit]bool, len(pR.Commits))
+	for len(sorted) < len(pR.Commits) {
+		progress := false
+		for _, comm := range pR.Commits {
+			if sortedSet[comm] || pending[comm] > 0 {
+				continue
+			}
+			sorted = append(sorted, comm)
+			sortedSet[comm] = true
+			progress = true
+			for _, dependent := range dependents[comm] {
+				pending[dependent]--
+			}
+		}
+		if !progress {
+			return nil, errCyclicDependency
+		}
+	}
+	return sorted, nil
+}
+
+// mergeDAG performs the action over each commit of the pull request once its dependencies are done
+// Commits whose dependencies failed are not performed, and fail with errFailedDependency
+// Once the ctx is done, the commits not started yet fail with its error
+// Notice that independent commits are performed concurrently, by at most Owner.DeliveryWorkers
+func (own *Owner) mergeDAG(ctx context.Context, pR *PullRequest, action func(*Commit) error) {
+	graph, err := pR.dependencies()
+	if err == nil {
+		_, err = pR.order(graph) // Rejects cyclic dependencies, which would never be ready
+	}
+	if err != nil { // Unreachable when the pull request was delegated
+		for _, comm := range pR.Commits {
+			own.report(&Result{CommitId: comm.Id, Error: err})
+		}
+		return
+	}
+
+	placeholders := pR.placeholders()
+	idxs := make(map[*Commit]int, len(pR.Commits))
+	pending := make(map[*Commit]int, len(pR.Commits)) // Qt of unfinished dependencies
+	dependents := make(map[*Commit][]*Commit)
+	for i, comm := range pR.Commits {
+		idxs[comm] = i
+		pending[comm] = len(graph[comm])
+		for _, dep := range graph[comm] {
+			dependents[dep] = append(dependents[dep], comm)
+		}
+	}
+
+	errs := make([]error, len(pR.Commits)) // Written only by the worker of the commit
+	perform := func(comm *Commit) error {
+		for _, dep := range graph[comm] {
+			if errs[idxs[dep]] != nil || dep.Is(Rejected, Failed) {
+				if !comm.Is(Rejected) {
+					_ = comm.transition(Failed)
+					own.report(&Result{CommitId: comm.Id, Error: errFailedDependency})
+				}
+				return errFailedDependency
+			}
+		}
+		if err := ctx.Err(); err != nil { // The canceled orchestration doesn't start more commits
+			if !comm.Is(Rejected) {
+				_ = comm.transition(Failed)
+				own.report(resultOf(comm, err))
+			}
+			return err
+		}
+		comm.resolveRefs(graph[comm], placeholders)
+		return action(comm)
+	}
+
+	ready := make(chan *Commit, len(pR.Commits))
+	finished := make(chan *Commit, len(pR.Commits))
+	for _, comm := range pR.Commits {
+		if pending[comm] == 0 {
+			ready <- comm
+		}
+	}
+	workers := own.deliveryWorkers()
+	if workers > len(pR.Commits) {
+		workers = len(pR.Commits)
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for comm := range ready {
+				errs[idxs[comm]] = perform(comm)
+				finished <- comm
+			}
+		}()
+	}
+	for range pR.Commits { // Schedules the dependents once all its dependencies are finished
+		comm := <-finished
+		for _, dependent := range dependents[comm] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready <- dependent
+			}
+		}
+	}
+	close(ready)
+	wg.Wait()
+}
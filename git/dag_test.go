@@ -0,0 +1,183 @@
+package git
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/lib/pq"
+	"github.com/sebach1/rtc/integrity"
+)
+
+func TestPullRequest_order(t *testing.T) {
+	t.Parallel()
+	refTo := func(chg *Change, commId int64) *Change {
+		chg.EntityId = CommitRef(commId)
+		return chg
+	}
+	withEntity := func(chg *Change, id integrity.Id) *Change {
+		chg.EntityId = id
+		return chg
+	}
+	tests := []struct {
+		name    string
+		pR      *PullRequest
+		wantIds []int64
+		wantErr error
+	}{
+		{
+			name: "INDEPENDENT commits preserve its order",
+			pR: &PullRequest{Commits: []*Commit{
+				{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}},
+				{Id: 2, Changes: []*Change{gChanges.Foo.Update.copy(t)}},
+			}},
+			wantIds: []int64{1, 2},
+		},
+		{
+			name: "DECLARED and REFERENCED dependencies are sorted before its dependents",
+			pR: &PullRequest{Commits: []*Commit{
+				{Id: 1, Changes: []*Change{refTo(gChanges.Foo.Update.copy(t), 3)}},
+				{Id: 2, Changes: []*Change{gChanges.Foo.Update.copy(t)}, DependsOn: pq.Int64Array{1}},
+				{Id: 3, Changes: []*Change{gChanges.Foo.Create.copy(t)}},
+			}},
+			wantIds: []int64{3, 1, 2},
+		},
+		{
+			name: "users of TEMPORARY entities are sorted after its creation",
+			pR: &PullRequest{Commits: []*Commit{
+				{Id: 1, Changes: []*Change{withEntity(gChanges.Foo.Update.copy(t), "tmp:foo")}},
+				{Id: 2, Changes: []*Change{withEntity(gChanges.Foo.Create.copy(t), "tmp:foo")}},
+			}},
+			wantIds: []int64{2, 1},
+		},
+		{
+			name: "UNRESOLVED TEMPORARY entity",
+			pR: &PullRequest{Commits: []*Commit{
+				{Id: 1, Changes: []*Change{withEntity(gChanges.Foo.Update.copy(t), "tmp:foo")}},
+			}},
+			wantErr: errUnresolvedTemporaryId,
+		},
+		{
+			name: "CYCLIC dependencies",
+			pR: &PullRequest{Commits: []*Commit{
+				{Id: 1, Changes: []*Change{gChanges.Foo.Update.copy(t)}, DependsOn: pq.Int64Array{2}},
+				{Id: 2, Changes: []*Change{gChanges.Foo.Update.copy(t)}, DependsOn: pq.Int64Array{1}},
+			}},
+			wantErr: errCyclicDependency,
+		},
+		{
+			name: "UNKNOWN dependency",
+			pR: &PullRequest{Commits: []*Commit{
+				{Id: 1, Changes: []*Change{gChanges.Foo.Update.copy(t)}, DependsOn: pq.Int64Array{2}},
+			}},
+			wantErr: errUnknownDependency,
+		},
+		{
+			name: "REFERENCE to a NON-CREATE commit",
+			pR: &PullRequest{Commits: []*Commit{
+				{Id: 1, Changes: []*Change{refTo(gChanges.Foo.Update.copy(t), 2)}},
+				{Id: 2, Changes: []*Change{gChanges.Foo.Update.copy(t)}},
+			}},
+			wantErr: errInvalidReference,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			graph, err := tt.pR.dependencies()
+			var sorted []*Commit
+			if err == nil {
+				sorted, err = tt.pR.order(graph)
+			}
+			if err != tt.wantErr {
+				t.Errorf("PullRequest.order() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var gotIds []int64
+			for _, comm := range sorted {
+				gotIds = append(gotIds, comm.Id)
+			}
+			if diff := cmp.Diff(tt.wantIds, gotIds); diff != "" {
+				t.Errorf("PullRequest.order() mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestOwner_mergeDAG(t *testing.T) {
+	t.Parallel()
+	created := gChanges.Foo.Create.copy(t)
+	created.EntityId = "fooCreatedId" // As it was returned by the collaborator
+	dependent := gChanges.Foo.Update.copy(t)
+	dependent.EntityId = CommitRef(1)
+	tmpCreated := gChanges.Foo.Create.copy(t)
+	tmpCreated.EntityId = "tmp:foo"
+	tmpDependent := gChanges.Foo.Update.copy(t)
+	tmpDependent.EntityId = "tmp:foo"
+
+	tests := []struct {
+		name          string
+		pR            *PullRequest
+		failedIds     map[int64]bool
+		wantEntityIds []integrity.Id
+		wantQtResErrs int
+	}{
+		{
+			name: "REFERENCES are RESOLVED with the returned entity ids",
+			pR: &PullRequest{Commits: []*Commit{
+				{Id: 2, Changes: []*Change{dependent.copy(t)}},
+				{Id: 1, Changes: []*Change{created.copy(t)}},
+			}},
+			wantEntityIds: []integrity.Id{"fooCreatedId", "fooCreatedId"},
+		},
+		{
+			name: "TEMPORARY ids are RESOLVED with the returned entity ids",
+			pR: &PullRequest{Commits: []*Commit{
+				{Id: 2, Changes: []*Change{tmpDependent.copy(t)}},
+				{Id: 1, Changes: []*Change{tmpCreated.copy(t)}},
+			}},
+			wantEntityIds: []integrity.Id{"fooCreatedId", "fooCreatedId"},
+		},
+		{
+			name: "DEPENDENTS of a FAILED commit are NOT performed",
+			pR: &PullRequest{Commits: []*Commit{
+				{Id: 2, Changes: []*Change{dependent.copy(t)}},
+				{Id: 1, Changes: []*Change{created.copy(t)}},
+			}},
+			failedIds:     map[int64]bool{1: true},
+			wantEntityIds: []integrity.Id{CommitRef(1), "fooCreatedId"},
+			wantQtResErrs: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			own := &Owner{Waiter: &sync.WaitGroup{}, Summary: make(chan *Result, len(tt.pR.Commits))}
+			own.mergeDAG(context.Background(), tt.pR, func(comm *Commit) error {
+				if tt.failedIds[comm.Id] {
+					return errFoo
+				}
+				for _, chg := range comm.Changes {
+					if chg.Type == "create" { // As the collaborator returns the created entity
+						chg.EntityId = "fooCreatedId"
+					}
+				}
+				return nil
+			})
+			close(own.Summary)
+			var gotEntityIds []integrity.Id
+			for _, comm := range tt.pR.Commits {
+				gotEntityIds = append(gotEntityIds, comm.Changes[0].EntityId)
+			}
+			if diff := cmp.Diff(tt.wantEntityIds, gotEntityIds); diff != "" {
+				t.Errorf("Owner.mergeDAG() entity ids mismatch (-want +got): %s", diff)
+			}
+			if got := len(own.Summary); got != tt.wantQtResErrs {
+				t.Errorf("Owner.mergeDAG() gotQtResErrs = %v, want %v", got, tt.wantQtResErrs)
+			}
+		})
+	}
+}
@@ -30,6 +30,25 @@ var (
 	errMixedTables     = errors.New("the TABLES over the commit are MIXED")
 	errMixedOpts       = errors.New("the OPTIONS over the commit are MIXED")
 	errNilBranchId     = errors.New("the commit's BRANCH ID is NIL")
+	errForeignCommit   = errors.New("the commit does NOT BELONG to the given BRANCH")
+	errForeignSchema   = errors.New("the BRANCH is BOUND to ANOTHER SCHEMA")
+
+	// Merge
+	errSameBranch    = errors.New("the SOURCE and TARGET BRANCHES are the SAME")
+	errMergeInFlight = errors.New("the BRANCH has commits BEING ORCHESTRATED")
+
+	// Stash
+	errNilStashName   = errors.New("the STASH NAME is NIL")
+	errStashInUse     = errors.New("the STASH NAME is ALREADY IN USE by the branch")
+	errStashNotFound  = errors.New("the STASH is NOT FOUND in the branch")
+	errNothingToStash = errors.New("the INDEX has NOT UNCOMMITTED CHANGES to stash")
+
+	// Revert
+	errUnmergedRevert     = errors.New("the commit CANNOT be REVERTED due it is NOT MERGED")
+	errIrreversibleType   = errors.New("the TYPE of the commit is NOT REVERSIBLE")
+	errNilPreImage        = errors.New("the PRE-IMAGE of the commit is NIL")
+	errIncompletePreImage = errors.New("the PRE-IMAGE does NOT DESCRIBE all the COLUMNS of the commit")
+	errRevertInFlight     = errors.New("the commit is ALREADY BEING REVERTED")
 
 	// Community
 	errSchemaNotFoundInCommunity = errors.New("the SCHEMA NAME provided is NOT FOUND in the community")
@@ -39,11 +58,32 @@ var (
 	errTableInUse      = errors.New("the TABLE is ALREADY IN USE by a member")
 	errNoCollaborators = errors.New("there are NOT COLLABORATORS to achieve this TABLE")
 	errNoMembers       = errors.New("there are NOT MEMBERS to achieve this TABLE")
+	errCircuitOpen     = errors.New("the CIRCUIT BREAKER of the member is OPEN")
+	errUnknownStrategy = errors.New("the SELECTION STRATEGY of the team is UNKNOWN")
 
 	// Owner
 	errNilProject   = errors.New("the PROJECT is NIL")
 	errEmptyProject = errors.New("the PROJECT does NOT contain ANY SCHEMA")
+	errSagaAborted  = errors.New("the SAGA was ABORTED due a REJECTED COMMIT")
+	errNoSchema     = errors.New("the SCHEMA of the commit's TABLE is NOT FOUND in the PROJECT")
+
+	// Dependencies
+	errUnknownDependency = errors.New("the DEPENDENCY is NOT any COMMIT of the PULL REQUEST")
+	errInvalidReference  = errors.New("the REFERENCED commit is NOT a CREATE")
+	errCyclicDependency  = errors.New("the DEPENDENCIES between commits are CYCLIC")
+	errFailedDependency  = errors.New("the commit DEPENDS ON a commit which was NOT MERGED")
+
+	// Temporary ids
+	errUnresolvedTemporaryId = errors.New("the TEMPORARY ID is NOT CREATED by any COMMIT nor RESOLVED")
+
+	// Two-phase commit
+	errTwoPhaseAborted = errors.New("the TWO-PHASE COMMIT was ABORTED")
 
 	// Branch
 	errNilIndexId = errors.New("the branch's INDEX ID is NIL")
+
+	// History
+	errUnknownCommitState = errors.New("the commit STATE is UNKNOWN")
+	errInvalidTransition  = errors.New("the commit CANNOT TRANSITION to the given STATE")
+	errNegativeLimit      = errors.New("the LIMIT cannot be NEGATIVE")
 )
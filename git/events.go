@@ -0,0 +1,172 @@
+package git
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sebach1/rtc/integrity"
+)
+
+// An EventType is a kind of progress of an orchestration
+type EventType string
+
+const (
+	// EventDelegateStarted is emitted when the owner starts to delegate the pull request
+	EventDelegateStarted EventType = "delegate.started"
+	// EventDelegateFinished is emitted when the delegation of the pull request ends, successfully or not
+	EventDelegateFinished EventType = "delegate.finished"
+	// EventReviewStarted is emitted when a commit starts to be reviewed against the schema
+	EventReviewStarted EventType = "review.started"
+	// EventReviewerAssigned is emitted when the team chooses the reviewer of a commit. See Team.Delegate
+	EventReviewerAssigned EventType = "reviewer.assigned"
+	// EventCommitRejected is emitted when a commit didn't pass its review
+	EventCommitRejected EventType = "commit.rejected"
+	// EventCommitDelegated is emitted when a commit passed its review
+	EventCommitDelegated EventType = "commit.delegated"
+	// EventCallStarted is emitted when a commit starts to be delivered to its reviewer
+	EventCallStarted EventType = "call.started"
+	// EventCallFinished is emitted when the delivery of a commit finished, successfully or not
+	EventCallFinished EventType = "call.finished"
+	// EventLedgerFailed is emitted when an applied commit couldn't be recorded onto the ledger, so it
+	// could be applied again by a later orchestration. See Owner.Ledger
+	EventLedgerFailed EventType = "ledger.failed"
+	// EventAbortFailed is emitted when a prepared commit which wasn't committed couldn't be aborted, so
+	// its preparation could be left open by its reviewer. See Preparer.Abort
+	EventAbortFailed EventType = "abort.failed"
+	// EventMergeDone is emitted when all the commits of the pull request were merged or failed
+	EventMergeDone EventType = "merge.done"
+	// EventOrchestrationFailed is emitted when the pull request couldn't be delegated
+	EventOrchestrationFailed EventType = "orchestration.failed"
+)
+
+// Final tells if no other event is emitted by the orchestration after this kind of event
+func (t EventType) Final() bool {
+	return t == EventMergeDone || t == EventOrchestrationFailed
+}
+
+// An Event describes the progress of an orchestration
+type Event struct {
+	Type          EventType `json:"type,omitempty"`
+	PullRequestId int64     `json:"pull_request_id,omitempty"`
+	CommitId      int64     `json:"commit_id,omitempty"`
+
+	// TableName and Collaborator are the table of the commit and the type of the reviewer it was assigned to
+	TableName    integrity.TableName `json:"table_name,omitempty"`
+	Collaborator string              `json:"collaborator,omitempty"`
+
+	// Result is the outcome of the commit, given on its rejection and on the end of its calls
+	Result *Result `json:"result,omitempty"`
+	// Error is the reason of the failure of the orchestration or its delegation
+	Error string `json:"error,omitempty"`
+
+	At time.Time `json:"at,omitempty"`
+}
+
+// A Listener observes the lifecycle of the orchestrations of an Owner. See Owner.Listeners
+// Notice it's called synchronously by the goroutine which performs each step, so it must be safe
+// for concurrent use, it shouldn't block, and it mustn't modify the given event
+type Listener interface {
+	OnEvent(*Event)
+}
+
+// ListenerFunc is the adapter which lets any func be a Listener
+type ListenerFunc func(*Event)
+
+// OnEvent calls the func with the given event
+func (f ListenerFunc) OnEvent(ev *Event) {
+	f(ev)
+}
+
+// PullRequestTopic is the topic the events of the orchestration of the given pull request are published onto
+func PullRequestTopic(pullRequestId int64) string {
+	return fmt.Sprintf("pull_request:%d", pullRequestId)
+}
+
+// defaultSubscriptionBuffer is the qt of events a subscriber can be behind the publisher
+const defaultSubscriptionBuffer = 64
+
+// A Broadcaster fans out the events of the orchestrations to its subscribers, by topic
+// Notice the publications never block: the events which doesn't fit onto the buffer of
+// a slow subscriber are dropped for it
+type Broadcaster struct {
+	mu   sync.Mutex
+	subs map[string]map[chan *Event]struct{}
+}
+
+// NewBroadcaster returns a Broadcaster without subscribers
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{subs: make(map[string]map[chan *Event]struct{})}
+}
+
+// Subscribe retrieves the channel which receives the events published onto the topic, and
+// the func which cancels the subscription, closing the channel
+func (b *Broadcaster) Subscribe(topic string) (<-chan *Event, func()) {
+	ch := make(chan *Event, defaultSubscriptionBuffer)
+	b.mu.Lock()
+	if b.subs[topic] == nil {
+		b.subs[topic] = make(map[chan *Event]struct{})
+	}
+	b.subs[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs[topic], ch)
+			if len(b.subs[topic]) == 0 {
+				delete(b.subs, topic)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Publish sends the event to the current subscribers of the topic
+func (b *Broadcaster) Publish(topic string, ev *Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[topic] {
+		select {
+		case ch <- ev:
+		default: // Slow subscriber
+		}
+	}
+}
+
+// resultEvent retrieves the event of the given type about the result of a commit
+// Notice the result is copied, as the original one keeps being modified by the orchestration
+func resultEvent(t EventType, res *Result) *Event {
+	cp := *res
+	cp.describe()
+	return &Event{Type: t, CommitId: res.CommitId, Result: &cp}
+}
+
+// topicsListener publishes the events onto the broadcaster, by the topic of its pull request and the given topics
+type topicsListener struct {
+	broadcaster *Broadcaster
+	topics      []string
+}
+
+func (l *topicsListener) OnEvent(ev *Event) {
+	if ev.PullRequestId != 0 {
+		l.broadcaster.Publish(PullRequestTopic(ev.PullRequestId), ev)
+	}
+	for _, topic := range l.topics {
+		l.broadcaster.Publish(topic, ev)
+	}
+}
+
+// emit notifies the event of the orchestration to the listeners of the owner
+func (own *Owner) emit(ev *Event) {
+	if len(own.Listeners) == 0 {
+		return
+	}
+	ev.PullRequestId = own.pullRequestId
+	ev.At = time.Now()
+	for _, l := range own.Listeners {
+		l.OnEvent(ev)
+	}
+}
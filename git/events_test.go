@@ -0,0 +1,157 @@
+package git
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/schema"
+)
+
+func TestBroadcaster(t *testing.T) {
+	t.Parallel()
+	b := NewBroadcaster()
+	foo, cancelFoo := b.Subscribe("foo")
+	bar, cancelBar := b.Subscribe("bar")
+	defer cancelBar()
+
+	b.Publish("foo", &Event{Type: EventCallStarted})
+	if ev := <-foo; ev.Type != EventCallStarted {
+		t.Errorf("Broadcaster.Publish() event = %v, want %v", ev.Type, EventCallStarted)
+	}
+	select {
+	case ev := <-bar:
+		t.Errorf("Broadcaster.Publish() published %v onto a FOREIGN topic", ev.Type)
+	default:
+	}
+
+	for i := 0; i < defaultSubscriptionBuffer+1; i++ { // Must not block on the slow subscriber
+		b.Publish("bar", &Event{Type: EventCallFinished})
+	}
+	if len(bar) != defaultSubscriptionBuffer {
+		t.Errorf("Broadcaster.Publish() buffered %v events, want %v", len(bar), defaultSubscriptionBuffer)
+	}
+
+	cancelFoo()
+	cancelFoo() // Idempotent
+	if _, ok := <-foo; ok {
+		t.Errorf("Broadcaster.Subscribe() cancel did NOT CLOSE the subscription")
+	}
+	b.Publish("foo", &Event{Type: EventMergeDone}) // Without subscribers
+}
+
+func TestOwner_Orchestrate_events(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		commits    []*Commit
+		wantEvents []EventType
+	}{
+		{
+			name:    "MERGED commit",
+			commits: []*Commit{{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}}},
+			wantEvents: []EventType{
+				EventDelegateStarted, EventReviewStarted, EventReviewerAssigned, EventCommitDelegated,
+				EventDelegateFinished, EventCallStarted, EventCallFinished, EventMergeDone,
+			},
+		},
+		{
+			name: "REJECTED commit",
+			commits: []*Commit{
+				{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t), gChanges.Foo.Update.copy(t)}},
+			},
+			wantEvents: []EventType{
+				EventDelegateStarted, EventReviewStarted, EventCommitRejected, EventDelegateFinished, EventMergeDone,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			b := NewBroadcaster()
+			events, cancel := b.Subscribe("foo")
+			defer cancel()
+			prEvents, cancelPR := b.Subscribe(PullRequestTopic(2))
+			defer cancelPR()
+
+			own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+			WithBroadcaster(b, "foo")(own)
+			pR := &PullRequest{Id: 2, Commits: tt.commits}
+			own.Waiter.Add(1)
+			go own.Orchestrate(context.Background(), &Community{gTeams.Foo.copy(t).mock(gTables.Foo.Name, nil)}, gSchemas.Foo.Name, pR)
+			err := own.WaitAndClose()
+			if err != nil {
+				t.Fatalf("Owner.Orchestrate() error = %v", err)
+			}
+
+			var got []EventType
+			for len(events) > 0 {
+				ev := <-events
+				if ev.PullRequestId != pR.Id {
+					t.Errorf("Owner.Orchestrate() event of pull request %v, want %v", ev.PullRequestId, pR.Id)
+				}
+				got = append(got, ev.Type)
+			}
+			if diff := cmp.Diff(tt.wantEvents, got); diff != "" {
+				t.Errorf("Owner.Orchestrate() events mismatch (-want +got): %s", diff)
+			}
+			if len(prEvents) != len(tt.wantEvents) {
+				t.Errorf("Owner.Orchestrate() published %v events onto the pull request topic, want %v", len(prEvents), len(tt.wantEvents))
+			}
+		})
+	}
+}
+
+func TestWithListener(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var got []*Event
+	record := ListenerFunc(func(ev *Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, ev)
+	})
+
+	own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+	WithListener(record)(own)
+	pR := &PullRequest{Id: 2, Commits: []*Commit{{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}}}}
+	own.Waiter.Add(1)
+	go own.Orchestrate(context.Background(), &Community{gTeams.Foo.copy(t).mock(gTables.Foo.Name, nil)}, gSchemas.Foo.Name, pR)
+	err := own.WaitAndClose()
+	if err != nil {
+		t.Fatalf("Owner.Orchestrate() error = %v", err)
+	}
+
+	var assigned *Event
+	for _, ev := range got {
+		if ev.At.IsZero() {
+			t.Errorf("WithListener() event %v WITHOUT timestamp", ev.Type)
+		}
+		if ev.Type == EventReviewerAssigned {
+			assigned = ev
+		}
+	}
+	if assigned == nil {
+		t.Fatalf("WithListener() did NOT OBSERVE the assignment of the reviewer")
+	}
+	want := &Event{
+		Type: EventReviewerAssigned, PullRequestId: 2, CommitId: 1,
+		TableName: gTables.Foo.Name, Collaborator: "*git.collabMock", At: assigned.At,
+	}
+	if diff := cmp.Diff(want, assigned); diff != "" {
+		t.Errorf("WithListener() assignment mismatch (-want +got): %s", diff)
+	}
+
+	own = newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+	WithListener(record)(own)
+	got = nil
+	_, err = own.Delegate(context.Background(), &Community{}, gSchemas.Foo.Name, &PullRequest{})
+	if err == nil {
+		t.Fatalf("Owner.Delegate() EXPECTED an error")
+	}
+	if len(got) != 2 || got[1].Type != EventDelegateFinished || got[1].Error != err.Error() {
+		t.Errorf("WithListener() did NOT OBSERVE the failure of the delegation: %v", got)
+	}
+}
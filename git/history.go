@@ -1,5 +1,160 @@
 package git
 
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sebach1/rtc/integrity"
+)
+
+// defaultHistoryLimit is the page size used when the filter doesn't specify any
+const defaultHistoryLimit = 50
+
+// History is the log of the commits performed over a branch, ordered by its ids
 type History struct {
-	Commits []*Commit
+	Commits []*Commit `json:"commits,omitempty"`
+
+	// Next is the cursor to retrieve the following page of the log
+	// It's zero when there are no more commits to retrieve
+	Next int64 `json:"next,omitempty"`
+}
+
+// A HistoryFilter narrows down the commits retrieved from a History
+// Notice that the change-level criteria (TableName, EntityId, Type) matches a commit
+// when ANY of its changes satisfies them
+type HistoryFilter struct {
+	TableName integrity.TableName `json:"table_name,omitempty"`
+	EntityId  integrity.Id        `json:"entity_id,omitempty"`
+	Type      integrity.CRUD      `json:"type,omitempty"`
+	State     CommitState         `json:"state,omitempty"`
+
+	// After is the cursor from where the log starts (exclusive). See History.Next
+	After int64 `json:"after,omitempty"`
+	Limit int   `json:"limit,omitempty"`
+}
+
+// validate checks the consistency of the filter criteria
+func (f *HistoryFilter) validate() error {
+	if f.Type != "" {
+		err := f.Type.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	if f.State != "" {
+		err := f.State.Validate()
+		if err != nil {
+			return err
+		}
+	}
+	if f.Limit < 0 {
+		return errNegativeLimit
+	}
+	return nil
+}
+
+func (f *HistoryFilter) limit() int {
+	if f.Limit == 0 {
+		return defaultHistoryLimit
+	}
+	return f.Limit
+}
+
+// query builds the sql query which retrieves the commits of the given branch
+// Notice it asks for one more commit than the limit in order to know if there is a next page
+// The changes are left joined, so the commits without them are retrieved unless a change-level criteria is given
+func (f *HistoryFilter) query(branchId int64) (string, []interface{}) {
+	conds := []string{"commits.branch_id=?", "commits.id>?"}
+	args := []interface{}{branchId, f.After}
+
+	if f.TableName != "" {
+		conds = append(conds, "changes.table_name=?")
+		args = append(args, f.TableName)
+	}
+	if f.EntityId != "" {
+		conds = append(conds, "changes.entity_id=?")
+		args = append(args, f.EntityId)
+	}
+	if f.Type != "" {
+		conds = append(conds, "changes.type=?")
+		args = append(args, f.Type)
+	}
+	if f.State != "" {
+		conds = append(conds, "commits.state=?")
+		args = append(args, f.State)
+	}
+	args = append(args, f.limit()+1)
+
+	qr := `SELECT DISTINCT commits.* FROM commits LEFT JOIN changes ON changes.commit_id=commits.id WHERE ` +
+		strings.Join(conds, " AND ") + ` ORDER BY commits.id LIMIT ?`
+	return qr, args
+}
+
+// History retrieves the log of commits of the branch satisfying the given filter,
+// with its changes already fetched
+func (b *Branch) History(ctx context.Context, db *sqlx.DB, filter *HistoryFilter) (*History, error) {
+	if filter == nil {
+		filter = &HistoryFilter{}
+	}
+	err := filter.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	qr, args := filter.query(b.Id)
+	var comms []*Commit
+	err = db.SelectContext(ctx, &comms, qr, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	hist := &History{}
+	if len(comms) > filter.limit() {
+		comms = comms[:filter.limit()]
+		hist.Next = comms[len(comms)-1].Id
+	}
+	hist.Commits = comms
+
+	err = fetchCommitsChanges(ctx, db, comms)
+	if err != nil {
+		return nil, err
+	}
+	err = fetchCommitsTransitions(ctx, db, comms)
+	if err != nil {
+		return nil, err
+	}
+	return hist, nil
+}
+
+// fetchCommitsChanges retrieves the changes of all the given commits in a single query
+// and assigns them to the .Changes field of its belonging commit
+func fetchCommitsChanges(ctx context.Context, db *sqlx.DB, comms []*Commit) error {
+	if len(comms) == 0 {
+		return nil
+	}
+	commsById := make(map[int64]*Commit, len(comms))
+	var ids []int64
+	for _, comm := range comms {
+		commsById[comm.Id] = comm
+		ids = append(ids, comm.Id)
+	}
+
+	qr, args, err := sqlx.In(`SELECT * FROM changes WHERE commit_id IN (?) ORDER BY id`, ids)
+	if err != nil {
+		return err
+	}
+	var chgs []*Change
+	err = db.SelectContext(ctx, &chgs, qr, args...)
+	if err != nil {
+		return err
+	}
+	for _, chg := range chgs {
+		comm, ok := commsById[chg.CommitId]
+		if !ok {
+			continue
+		}
+		comm.Changes = append(comm.Changes, chg)
+	}
+	return nil
 }
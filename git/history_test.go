@@ -0,0 +1,210 @@
+package git
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/internal/test/assist"
+	"github.com/sebach1/rtc/internal/test/thelper"
+)
+
+func TestHistoryFilter_query(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		filter    *HistoryFilter
+		branchId  int64
+		wantQr    string
+		wantQtArg int
+	}{
+		{
+			name:      "zero filter",
+			filter:    &HistoryFilter{},
+			branchId:  1,
+			wantQr:    `SELECT DISTINCT commits.* FROM commits LEFT JOIN changes ON changes.commit_id=commits.id WHERE commits.branch_id=? AND commits.id>? ORDER BY commits.id LIMIT ?`,
+			wantQtArg: 3,
+		},
+		{
+			name:     "full filter",
+			filter:   &HistoryFilter{TableName: "foo", EntityId: "bar", Type: "update", State: Pending, After: 3, Limit: 5},
+			branchId: 1,
+			wantQr: `SELECT DISTINCT commits.* FROM commits LEFT JOIN changes ON changes.commit_id=commits.id WHERE ` +
+				`commits.branch_id=? AND commits.id>? AND changes.table_name=? AND changes.entity_id=? AND changes.type=? ` +
+				`AND commits.state=? ORDER BY commits.id LIMIT ?`,
+			wantQtArg: 7,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gotQr, gotArgs := tt.filter.query(tt.branchId)
+			if diff := cmp.Diff(tt.wantQr, gotQr); diff != "" {
+				t.Errorf("HistoryFilter.query() mismatch (-want +got): %s", diff)
+			}
+			if len(gotArgs) != tt.wantQtArg {
+				t.Errorf("HistoryFilter.query() args qt = %v, want %v", len(gotArgs), tt.wantQtArg)
+			}
+			if gotArgs[len(gotArgs)-1] != tt.filter.limit()+1 {
+				t.Errorf("HistoryFilter.query() limit arg = %v, want %v", gotArgs[len(gotArgs)-1], tt.filter.limit()+1)
+			}
+		})
+	}
+}
+
+func TestBranch_History(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		filter *HistoryFilter
+	}
+	commCols := []string{"id", "branch_id", "state"}
+	trCols := []string{"id", "commit_id", "from_state", "to_state"}
+	chgCols := []string{"id", "table_name", "column_name", "commit_id"}
+	tests := []struct {
+		name    string
+		branch  *Branch
+		args    args
+		stubs   []*assist.QueryStubber
+		want    *History
+		wantErr error
+	}{
+		{
+			name:    "commits query returns ERR",
+			branch:  gBranches.Foo.copy(t),
+			stubs:   []*assist.QueryStubber{{Expect: "SELECT DISTINCT commits.*", Err: errFoo}},
+			wantErr: errFoo,
+		},
+		{
+			name:    "INVALID STATE given",
+			branch:  gBranches.Foo.copy(t),
+			args:    args{filter: &HistoryFilter{State: "foo"}},
+			wantErr: errUnknownCommitState,
+		},
+		{
+			name:   "paginates and fetches the changes and transitions",
+			branch: gBranches.Foo.copy(t),
+			args:   args{filter: &HistoryFilter{Limit: 1}},
+			stubs: []*assist.QueryStubber{
+				{
+					Expect: "SELECT DISTINCT commits.*",
+					Rows:   sqlmock.NewRows(commCols).AddRow(1, gBranches.Foo.Id, Merged).AddRow(2, gBranches.Foo.Id, Pending),
+				},
+				{
+					Expect: "SELECT * FROM changes WHERE commit_id IN (?)",
+					Rows:   sqlmock.NewRows(chgCols).AddRow(10, "foo", "bar", 1),
+				},
+				{
+					Expect: "SELECT * FROM transitions WHERE commit_id IN (?)",
+					Rows:   sqlmock.NewRows(trCols).AddRow(20, 1, InFlight, Merged),
+				},
+			},
+			want: &History{
+				Commits: []*Commit{
+					{Id: 1, BranchId: gBranches.Foo.Id, State: Merged,
+						Changes:     []*Change{{Id: 10, TableName: "foo", ColumnName: "bar", CommitId: 1}},
+						Transitions: []*Transition{{Id: 20, CommitId: 1, FromState: InFlight, ToState: Merged}}},
+				},
+				Next: 1,
+			},
+		},
+		{
+			name:   "retrieves the commits WITHOUT changes",
+			branch: gBranches.Foo.copy(t),
+			stubs: []*assist.QueryStubber{
+				{
+					Expect: "SELECT DISTINCT commits.*",
+					Rows:   sqlmock.NewRows(commCols).AddRow(1, gBranches.Foo.Id, Pending).AddRow(2, gBranches.Foo.Id, Pending),
+				},
+				{
+					Expect: "SELECT * FROM changes WHERE commit_id IN (?, ?)",
+					Rows:   sqlmock.NewRows(chgCols).AddRow(10, "foo", "bar", 2),
+				},
+				{Expect: "SELECT * FROM transitions WHERE commit_id IN (?, ?)", Rows: sqlmock.NewRows(trCols)},
+			},
+			want: &History{
+				Commits: []*Commit{
+					{Id: 1, BranchId: gBranches.Foo.Id, State: Pending},
+					{Id: 2, BranchId: gBranches.Foo.Id, State: Pending,
+						Changes: []*Change{{Id: 10, TableName: "foo", ColumnName: "bar", CommitId: 2}}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := thelper.MockDB(t)
+			for _, stub := range tt.stubs {
+				stub.Stub(mock)
+			}
+			if tt.args.ctx == nil {
+				tt.args.ctx = context.Background()
+			}
+			got, err := tt.branch.History(tt.args.ctx, db, tt.args.filter)
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("Branch.History() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Branch.History() mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestLog_committed(t *testing.T) {
+	db, mock := mockDBWithOptions(t)
+	branch := gBranches.Foo.copy(t)
+	branchCols := []string{"id", "name", "index_id"}
+	chgCols := []string{"id", "table_name", "column_name", "entity_id", "index_id", "type", "commit_id"}
+	stubBranch := func() {
+		(&assist.QueryStubber{
+			Expect: "SELECT * FROM branches WHERE name=?",
+			Rows:   sqlmock.NewRows(branchCols).AddRow(branch.Id, branch.Name, branch.IndexId),
+		}).Stub(mock)
+	}
+
+	stubBranch()
+	(&assist.QueryStubber{Expect: "SELECT * FROM indices WHERE id=?", Rows: sqlmock.NewRows([]string{"id"}).AddRow(branch.IndexId)}).Stub(mock)
+	(&assist.QueryStubber{
+		Expect: "SELECT * FROM changes WHERE commit_id=0 AND index_id=?",
+		Rows:   sqlmock.NewRows(chgCols).AddRow(10, "fooTable", "fooColumn", "fooId", branch.IndexId, "update", 0),
+	}).Stub(mock)
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO commits")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), branch.Id, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	(&assist.ExecStubber{Expect: "UPDATE changes", Result: sqlmock.NewResult(0, 1)}).Stub(mock)
+
+	comms, err := Comm(context.Background(), db, integrity.BranchName(branch.Name))
+	if err != nil {
+		t.Fatalf("Comm() error = %v", err)
+	}
+	if len(comms) != 1 || comms[0].BranchId != branch.Id {
+		t.Fatalf("Comm() commits aren't performed over the branch %v: %v", branch.Id, comms)
+	}
+
+	stubBranch()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT commits.*")).
+		WithArgs(branch.Id, 0, defaultHistoryLimit+1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "branch_id"}).AddRow(comms[0].Id, comms[0].BranchId))
+	(&assist.QueryStubber{
+		Expect: "SELECT * FROM changes WHERE commit_id IN (?)",
+		Rows:   sqlmock.NewRows(chgCols).AddRow(10, "fooTable", "fooColumn", "fooId", branch.IndexId, "update", comms[0].Id),
+	}).Stub(mock)
+	(&assist.QueryStubber{Expect: "SELECT * FROM transitions WHERE commit_id IN (?)", Rows: sqlmock.NewRows([]string{"id"})}).Stub(mock)
+
+	hist, err := Log(context.Background(), db, integrity.BranchName(branch.Name), nil)
+	if err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if len(hist.Commits) != 1 || hist.Commits[0].Id != comms[0].Id {
+		t.Errorf("Log() doesn't show the commit %v: %v", comms[0].Id, hist.Commits)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
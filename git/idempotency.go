@@ -0,0 +1,168 @@
+package git
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/internal/store"
+)
+
+type idempotencyKeyCtxKey struct{}
+
+// WithIdempotencyKey returns a copy of the ctx which carries the idempotency key of the delivered commit
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, key)
+}
+
+// IdempotencyKeyFrom retrieves the idempotency key carried by the ctx. See Commit.IdempotencyKey
+// Collaborators should send it to its services in order to let them discard the repeated calls
+func IdempotencyKeyFrom(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyCtxKey{}).(string)
+	return key, ok && key != ""
+}
+
+// IdempotencyKey retrieves the key which identifies the application of the commit
+// It's derived from the identity of the commit and its changes, so it remains stable between
+// orchestrations of the same commit
+// Notice the commits which weren't stored yet (zero id) have no key, as they can't be told apart
+func (comm *Commit) IdempotencyKey() string {
+	if comm.Id == 0 {
+		return ""
+	}
+	type fingerprint struct {
+		TableName  string      `json:"t"`
+		ColumnName string      `json:"c"`
+		EntityId   string      `json:"e"`
+		Type       string      `json:"y"`
+		Value      interface{} `json:"v"`
+		Options    Options     `json:"o"`
+	}
+	fps := make([]fingerprint, 0, len(comm.Changes))
+	for _, chg := range comm.Changes {
+		fps = append(fps, fingerprint{
+			TableName:  string(chg.TableName),
+			ColumnName: string(chg.ColumnName),
+			EntityId:   string(chg.EntityId),
+			Type:       string(chg.Type),
+			Value:      chg.Value(),
+			Options:    chg.Options,
+		})
+	}
+	sort.Slice(fps, func(i, j int) bool {
+		if fps[i].TableName != fps[j].TableName {
+			return fps[i].TableName < fps[j].TableName
+		}
+		if fps[i].EntityId != fps[j].EntityId {
+			return fps[i].EntityId < fps[j].EntityId
+		}
+		return fps[i].ColumnName < fps[j].ColumnName
+	})
+	payload, err := json.Marshal(struct {
+		BranchId int64         `json:"b"`
+		CommitId int64         `json:"i"`
+		Changes  []fingerprint `json:"c"`
+	}{comm.BranchId, comm.Id, fps})
+	if err != nil { // Unreachable, as the values are validated
+		return ""
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// A Ledger keeps the results of the commits successfully applied, by its idempotency key
+// It lets the Owner skip the commits which were already applied by a previous orchestration
+type Ledger interface {
+	// Applied retrieves the result of the application of the given key, or nil if it wasn't applied
+	Applied(ctx context.Context, key string) (*Result, error)
+
+	// Record persists the result of a successful application
+	Record(ctx context.Context, res *Result) error
+}
+
+// sqlLedger is the Ledger which persists the results into the results table
+type sqlLedger struct {
+	db *sqlx.DB
+}
+
+// NewSQLLedger returns a Ledger backed by the given db
+func NewSQLLedger(db *sqlx.DB) Ledger {
+	return &sqlLedger{db: db}
+}
+
+func (l *sqlLedger) Applied(ctx context.Context, key string) (*Result, error) {
+	res := &Result{}
+	err := l.db.GetContext(ctx, res, `SELECT * FROM results WHERE idempotency_key=? AND error_message='' AND compensation=false ORDER BY id LIMIT 1`, key)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (l *sqlLedger) Record(ctx context.Context, res *Result) error {
+	return store.InsertIntoDB(ctx, l.db, res)
+}
+
+// applied checks if the commit with the given key was already applied, according to the owner .Ledger
+// If so, the commit takes the persisted result (e.g. the id of the created entity)
+func (own *Owner) applied(ctx context.Context, comm *Commit, key string) (bool, error) {
+	if own.Ledger == nil || key == "" {
+		return false, nil
+	}
+	res, err := own.Ledger.Applied(ctx, key)
+	if err != nil || res == nil {
+		return false, err
+	}
+	comm.Attempts = 0
+	if res.EntityId.IsNil() {
+		return true, nil
+	}
+	for _, chg := range comm.Changes {
+		if chg.EntityId.IsNil() || chg.EntityId.IsTemporary() {
+			chg.EntityId = res.EntityId
+		}
+	}
+	return true, nil
+}
+
+// ledgerRetryPolicy is the policy the writes onto the owner .Ledger are retried with
+// Notice any error is retried, as the commit was already applied
+var ledgerRetryPolicy = &RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 10 * time.Millisecond,
+	Multiplier:     2,
+	Retryable:      func(error) bool { return true },
+}
+
+// record persists the successful result of a delivery into the owner .Ledger
+// Notice its key must be taken before the delivery, as the collaborator response modifies the changes
+func (own *Owner) record(ctx context.Context, res *Result) error {
+	if own.Ledger == nil || res.IdempotencyKey == "" {
+		return nil
+	}
+	res.PullRequestId = own.pullRequestId
+	// The commit was already applied, so it must be recorded anyway
+	_, err := ledgerRetryPolicy.do(detach(ctx), nil, func(ctx context.Context) error {
+		return own.Ledger.Record(ctx, res)
+	})
+	return err
+}
+
+// recordApplied records the result of the applied commit, as Owner.record does
+// Notice a failed write doesn't fail the delivery, as the commit remains applied: it's reported apart
+// through an EventLedgerFailed
+func (own *Owner) recordApplied(ctx context.Context, comm *Commit, res *Result) {
+	err := own.record(ctx, res)
+	if err != nil {
+		own.emit(&Event{Type: EventLedgerFailed, CommitId: comm.Id, Result: res, Error: err.Error()})
+	}
+}
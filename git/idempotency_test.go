@@ -0,0 +1,205 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/internal/test/assist"
+	"github.com/sebach1/rtc/internal/test/thelper"
+	"github.com/sebach1/rtc/schema"
+)
+
+func TestCommit_IdempotencyKey(t *testing.T) {
+	t.Parallel()
+	updated := gChanges.Foo.Update.copy(t)
+	reupdated := gChanges.Foo.Update.copy(t)
+	err := reupdated.SetValue("anotherValue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		comm      *Commit
+		otherComm *Commit
+		wantEqual bool
+	}{
+		{
+			name:      "SAME commit with its changes UNORDERED",
+			comm:      &Commit{Id: 1, Changes: []*Change{updated.copy(t), gChanges.Foo.ColumnName.copy(t)}},
+			otherComm: &Commit{Id: 1, Changes: []*Change{gChanges.Foo.ColumnName.copy(t), updated.copy(t)}},
+			wantEqual: true,
+		},
+		{
+			name:      "SAME commit with DIFF VALUES",
+			comm:      &Commit{Id: 1, Changes: []*Change{updated.copy(t)}},
+			otherComm: &Commit{Id: 1, Changes: []*Change{reupdated.copy(t)}},
+			wantEqual: false,
+		},
+		{
+			name:      "DIFF commits with the SAME CHANGES",
+			comm:      &Commit{Id: 1, Changes: []*Change{updated.copy(t)}},
+			otherComm: &Commit{Id: 2, Changes: []*Change{updated.copy(t)}},
+			wantEqual: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, gotOther := tt.comm.IdempotencyKey(), tt.otherComm.IdempotencyKey()
+			if got == "" || gotOther == "" {
+				t.Fatalf("Commit.IdempotencyKey() returned an EMPTY key")
+			}
+			if (got == gotOther) != tt.wantEqual {
+				t.Errorf("Commit.IdempotencyKey() = %v and %v, want equal %v", got, gotOther, tt.wantEqual)
+			}
+		})
+	}
+
+	t.Run("UNSTORED commit has NO key", func(t *testing.T) {
+		t.Parallel()
+		if got := (&Commit{Changes: []*Change{updated.copy(t)}}).IdempotencyKey(); got != "" {
+			t.Errorf("Commit.IdempotencyKey() = %v, want empty", got)
+		}
+	})
+}
+
+func TestOwner_deliver_idempotency(t *testing.T) {
+	t.Parallel()
+	newComm := func() *Commit {
+		return &Commit{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}}
+	}
+	key := newComm().IdempotencyKey()
+	tests := []struct {
+		name           string
+		ledger         *ledgerMock
+		wantCalls      []integrity.CRUD
+		wantEntityId   integrity.Id
+		wantUnrecorded bool
+		wantErr        error
+	}{
+		{
+			name:         "NOT APPLIED commit is delivered with its key and recorded",
+			ledger:       &ledgerMock{},
+			wantCalls:    []integrity.CRUD{"create"},
+			wantEntityId: gChanges.Foo.Create.EntityId,
+		},
+		{
+			name:         "APPLIED commit is SKIPPED, taking the recorded entity",
+			ledger:       &ledgerMock{Results: map[string]*Result{key: {IdempotencyKey: key, EntityId: "fooCreatedId"}}},
+			wantEntityId: "fooCreatedId",
+		},
+		{
+			name:         "record FAILS TRANSIENTLY is retried",
+			ledger:       &ledgerMock{RecordErrs: []error{errFoo, errFoo}},
+			wantCalls:    []integrity.CRUD{"create"},
+			wantEntityId: gChanges.Foo.Create.EntityId,
+		},
+		{
+			name:           "record FAILS is reported apart, without failing the delivery",
+			ledger:         &ledgerMock{RecordErrs: []error{errFoo, errFoo, errFoo}},
+			wantCalls:      []integrity.CRUD{"create"},
+			wantEntityId:   gChanges.Foo.Create.EntityId,
+			wantUnrecorded: true,
+		},
+		{
+			name:    "ledger returns ERR",
+			ledger:  &ledgerMock{Err: errFoo},
+			wantErr: errFoo,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+			own.Ledger = tt.ledger
+			var ledgerFailures []*Event
+			own.Listeners = []Listener{ListenerFunc(func(ev *Event) {
+				if ev.Type == EventLedgerFailed {
+					ledgerFailures = append(ledgerFailures, ev)
+				}
+			})}
+			spy := &collabSpy{}
+			comm := newComm()
+			comm.Reviewer = spy
+			res, err := own.deliver(context.Background(), comm, "create")
+			if err != tt.wantErr {
+				t.Errorf("Owner.deliver() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.wantCalls, spy.Calls); diff != "" {
+				t.Errorf("Owner.deliver() calls mismatch (-want +got): %s", diff)
+			}
+			for _, gotKey := range spy.Keys {
+				if gotKey != key {
+					t.Errorf("Owner.deliver() collaborator received key %v, want %v", gotKey, key)
+				}
+			}
+			if res.Error != nil {
+				t.Errorf("Owner.deliver() result error = %v, want nil", res.Error)
+			}
+			if (tt.ledger.Results[key] == nil) != tt.wantUnrecorded {
+				t.Errorf("Owner.deliver() recorded %v, want %v", tt.ledger.Results[key] != nil, !tt.wantUnrecorded)
+			}
+			if (len(ledgerFailures) > 0) != tt.wantUnrecorded {
+				t.Errorf("Owner.deliver() emitted %v ledger failures, want unrecorded %v", len(ledgerFailures), tt.wantUnrecorded)
+			}
+			if got := comm.Changes[0].EntityId; got != tt.wantEntityId {
+				t.Errorf("Owner.deliver() entity id = %v, want %v", got, tt.wantEntityId)
+			}
+		})
+	}
+}
+
+func TestSQLLedger_Applied(t *testing.T) {
+	resCols := []string{"id", "commit_id", "idempotency_key", "entity_id", "attempts"}
+	tests := []struct {
+		name    string
+		stub    *assist.QueryStubber
+		want    *Result
+		wantErr error
+	}{
+		{
+			name: "NOT APPLIED key",
+			stub: &assist.QueryStubber{
+				Expect: "SELECT * FROM results WHERE idempotency_key=?",
+				Rows:   sqlmock.NewRows(resCols),
+			},
+		},
+		{
+			name: "APPLIED key",
+			stub: &assist.QueryStubber{
+				Expect: "SELECT * FROM results WHERE idempotency_key=?",
+				Rows:   sqlmock.NewRows(resCols).AddRow(1, 2, "foo", "fooId", 1),
+			},
+			want: &Result{Id: 1, CommitId: 2, IdempotencyKey: "foo", EntityId: "fooId", Attempts: 1},
+		},
+		{
+			name:    "query returns ERR",
+			stub:    &assist.QueryStubber{Expect: "SELECT * FROM results WHERE idempotency_key=?", Err: errFoo},
+			wantErr: errFoo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := thelper.MockDB(t)
+			tt.stub.Stub(mock)
+			got, err := NewSQLLedger(db).Applied(context.Background(), "foo")
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("sqlLedger.Applied() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("sqlLedger.Applied() mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
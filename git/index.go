@@ -79,7 +79,7 @@ func (idx *Index) FetchUncommittedChanges(ctx context.Context, db *sqlx.DB) (err
 	defer rows.Close()
 	for rows.Next() {
 		chg := Change{}
-		err = rows.StructScan(chg)
+		err = rows.StructScan(&chg)
 		if err != nil {
 			return
 		}
@@ -101,7 +101,7 @@ func (idx *Index) FetchChanges(ctx context.Context, db *sqlx.DB) (err error) {
 	defer rows.Close()
 	for rows.Next() {
 		chg := Change{}
-		err = rows.StructScan(chg)
+		err = rows.StructScan(&chg)
 		if err != nil {
 			return
 		}
@@ -114,26 +114,26 @@ func (idx *Index) FetchChanges(ctx context.Context, db *sqlx.DB) (err error) {
 	return
 }
 
-// Commit returns a persisted commit with the index's uncommitted changes.
-func (idx *Index) Commit(ctx context.Context, db *sqlx.DB) ([]*Commit, error) {
+// Commit returns a persisted commit with the index's uncommitted changes, performed over the given branch
+func (idx *Index) Commit(ctx context.Context, db *sqlx.DB, branchId int64) ([]*Commit, error) {
 	err := idx.FetchUncommittedChanges(ctx, db)
 	if err != nil {
 		return nil, err
 	}
-	comms, err := idx.commit(ctx, db)
+	comms, err := idx.commit(ctx, db, branchId)
 	if err != nil {
 		return nil, err
 	}
 	return comms, nil
 }
 
-func (idx *Index) commit(ctx context.Context, db *sqlx.DB) ([]*Commit, error) {
+func (idx *Index) commit(ctx context.Context, db *sqlx.DB, branchId int64) ([]*Commit, error) {
 	var comms []*Commit
 	comm := NewCommit(idx.Changes)
 
 	var batch []store.Storable
 	for _, changes := range comm.GroupBy(AreCompatible) {
-		comm := &Commit{Changes: changes}
+		comm := &Commit{BranchId: branchId, Changes: changes}
 		err := store.InsertIntoDB(ctx, db, comm)
 		if err != nil {
 			return nil, err
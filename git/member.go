@@ -1,9 +1,41 @@
 package git
 
-import "github.com/sebach1/rtc/integrity"
+import (
+	"time"
+
+	"github.com/sebach1/rtc/integrity"
+)
 
 // A Member is a Collaborator which has a table assigned
 type Member struct {
 	AssignedTable integrity.TableName `json:"assigned_table,omitempty"`
 	Collab        Collaborator        `json:"collab,omitempty"`
+
+	// RetryPolicy overrides the retry policy of the team for the calls to the Collab
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+	// Timeout overrides the timeout of the team for the deliveries to the Collab
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// MaxConcurrency caps the deliveries performed concurrently to the Collab. Zero means no cap
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+	// RateLimit throttles the calls to the Collab, besides the rate limit of the team
+	RateLimit *RateLimit `json:"rate_limit,omitempty"`
+
+	// Breaker stops the deliveries to the Collab while its service is down. Nil means no breaker
+	Breaker *CircuitBreaker `json:"breaker,omitempty"`
+	// Fallback is the collaborator the commits are delegated to while the circuit of the Breaker is
+	// open. If it's nil, they fail fast
+	Fallback Collaborator `json:"-"`
+
+	// Priority sorts the members assigned to the same table, from the lowest. See Team.Strategy
+	Priority int `json:"priority,omitempty"`
+	// Weight is the proportion of the commits the member is chosen for by the StrategyWeighted
+	// Zero means 1
+	Weight int `json:"weight,omitempty"`
+}
+
+func (m *Member) weight() int {
+	if m.Weight < 1 {
+		return 1
+	}
+	return m.Weight
 }
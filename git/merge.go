@@ -0,0 +1,208 @@
+package git
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/internal/store"
+)
+
+// A Conflict is a pair of changes of the merged branches which modify the same column of the same
+// entity with different values. See Overrides
+type Conflict struct {
+	Source *Change `json:"source,omitempty"`
+	Target *Change `json:"target,omitempty"`
+}
+
+// A MergeReport describes the merge of a branch into another one
+// Notice the merge only fast-forwards (moving the changes and commits onto the target) when there
+// are no conflicts, otherwise nothing is modified and the report lists them
+type MergeReport struct {
+	Source integrity.BranchName `json:"source,omitempty"`
+	Target integrity.BranchName `json:"target,omitempty"`
+
+	FastForward bool        `json:"fast_forward,omitempty"`
+	Conflicts   []*Conflict `json:"conflicts,omitempty"`
+
+	// Changes and Commits are the uncommitted changes and unmerged commits moved onto the target
+	Changes []*Change `json:"changes,omitempty"`
+	Commits []*Commit `json:"commits,omitempty"`
+}
+
+// mergeableStates are the states of the unmerged commits which can be moved to another branch
+// Notice the ones being orchestrated can't
+var mergeableStates = []CommitState{Pending, Rejected, Failed}
+
+// conflicts retrieves the conflicts between the source changes and the target ones
+func conflicts(source, target []*Change) (cflcts []*Conflict) {
+	for _, srcChg := range source {
+		for _, tgtChg := range target {
+			if Overrides(srcChg, tgtChg) && !srcChg.sameValue(tgtChg) {
+				cflcts = append(cflcts, &Conflict{Source: srcChg, Target: tgtChg})
+			}
+		}
+	}
+	return
+}
+
+// pendingChanges retrieves the uncommitted changes of the branch index and the changes of its unmerged commits
+func (b *Branch) pendingChanges(ctx context.Context, db *sqlx.DB) ([]*Change, []*Commit, error) {
+	chgs, err := uncommittedChanges(ctx, db, b.IndexId)
+	if err != nil {
+		return nil, nil, err
+	}
+	comms, err := b.UnmergedCommits(ctx, db)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = fetchCommitsChanges(ctx, db, comms)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chgs, comms, nil
+}
+
+// MergeBranch brings the uncommitted changes and unmerged commits of the source branch into the target one
+// It fast-forwards in case they don't conflict with the ones of the target, otherwise the conflicts are reported
+// Notice the changes duplicated by the target are discarded, as well as the commits left without changes, and
+// the temporary ids already resolved by the source are substituted, as they're only known by it
+func MergeBranch(
+	ctx context.Context,
+	db *sqlx.DB,
+	sourceName integrity.BranchName,
+	targetName integrity.BranchName,
+) (*MergeReport, error) {
+	if sourceName == targetName {
+		return nil, errSameBranch
+	}
+	source, err := BranchByName(ctx, db, sourceName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find source branch by name")
+	}
+	target, err := BranchByName(ctx, db, targetName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find target branch by name")
+	}
+	srcChgs, srcComms, err := source.pendingChanges(ctx, db)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch source pending changes")
+	}
+	tgtChgs, tgtComms, err := target.pendingChanges(ctx, db)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch target pending changes")
+	}
+	for _, comm := range srcComms {
+		if !comm.Is(mergeableStates...) {
+			return nil, errMergeInFlight
+		}
+	}
+
+	report := &MergeReport{Source: sourceName, Target: targetName}
+	for _, comm := range tgtComms {
+		tgtChgs = append(tgtChgs, comm.Changes...)
+	}
+	pending := srcChgs
+	for _, comm := range srcComms {
+		pending = append(pending, comm.Changes...)
+	}
+	report.Conflicts = conflicts(pending, tgtChgs)
+	if len(report.Conflicts) > 0 {
+		return report, nil
+	}
+
+	resolved, err := source.TemporaryIds(ctx, db)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch source temporary ids")
+	}
+	tgtIdx := &Index{Changes: tgtChgs}
+	var movedChgs, movedComms []store.Storable
+	var dups []*Change
+	var emptied []*Commit
+	move := func(chgs []*Change) (kept []*Change) {
+		for _, chg := range chgs {
+			if tgtIdx.containsChange(chg) {
+				dups = append(dups, chg)
+				continue
+			}
+			chg.resolveTemporaryIds(resolved)
+			chg.IndexId = target.IndexId
+			movedChgs = append(movedChgs, chg)
+			kept = append(kept, chg)
+		}
+		return
+	}
+	report.Changes = move(srcChgs)
+	droppedComms := make(map[int64]bool)
+	for _, comm := range srcComms {
+		comm.Changes = move(comm.Changes)
+		if len(comm.Changes) == 0 { // All of its changes are already on the target
+			droppedComms[comm.Id] = true
+			emptied = append(emptied, comm)
+			continue
+		}
+		comm.BranchId = target.Id
+		movedComms = append(movedComms, comm)
+		report.Commits = append(report.Commits, comm)
+	}
+	for _, comm := range report.Commits {
+		comm.DependsOn = withoutIds(comm.DependsOn, droppedComms)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer tx.Rollback() // No-op once committed
+	for _, chg := range dups {
+		err = store.DeleteFromDB(ctx, tx, chg)
+		if err != nil {
+			return nil, errors.Wrap(err, "delete duplicated change from db")
+		}
+	}
+	for _, comm := range emptied {
+		err = deleteCommit(ctx, tx, comm)
+		if err != nil {
+			return nil, errors.Wrap(err, "delete empty commit from db")
+		}
+	}
+	err = store.UpdateIntoDB(ctx, tx, movedChgs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "update moved changes into db")
+	}
+	err = store.UpdateIntoDB(ctx, tx, movedComms...)
+	if err != nil {
+		return nil, errors.Wrap(err, "update moved commits into db")
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "commit tx")
+	}
+	report.FastForward = true
+	return report, nil
+}
+
+// deleteCommit deletes the commit along with its transitions
+func deleteCommit(ctx context.Context, db sqlx.ExtContext, comm *Commit) error {
+	_, err := db.ExecContext(ctx, `DELETE FROM transitions WHERE commit_id=?`, comm.Id)
+	if err != nil {
+		return errors.Wrap(err, "delete commit transitions")
+	}
+	return store.DeleteFromDB(ctx, db, comm)
+}
+
+// withoutIds retrieves the given ids but the excluded ones
+func withoutIds(ids pq.Int64Array, excluded map[int64]bool) pq.Int64Array {
+	if len(ids) == 0 || len(excluded) == 0 {
+		return ids
+	}
+	var kept pq.Int64Array
+	for _, id := range ids {
+		if !excluded[id] {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
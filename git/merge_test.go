@@ -0,0 +1,79 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_conflicts(t *testing.T) {
+	t.Parallel()
+	diffValue := gChanges.Foo.Update.copy(t)
+	err := diffValue.SetValue("diffFooValue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diffCreate := gChanges.Foo.Create.copy(t)
+	err = diffCreate.SetValue("diffFooValue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bytesChg := func(val string) *Change {
+		chg := gChanges.Foo.Update.copy(t)
+		err := chg.SetValue([]byte(val))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return chg
+	}
+	srcBytes, tgtBytes := bytesChg("foo"), bytesChg("bar")
+	tests := []struct {
+		name   string
+		source []*Change
+		target []*Change
+		want   []*Conflict
+	}{
+		{
+			name:   "SAME column of the SAME entity with DIFF values",
+			source: []*Change{gChanges.Foo.Update, gChanges.Bar.Update},
+			target: []*Change{diffValue},
+			want:   []*Conflict{{Source: gChanges.Foo.Update, Target: diffValue}},
+		},
+		{
+			name:   "SAME column of the SAME entity with SAME values",
+			source: []*Change{gChanges.Foo.Update},
+			target: []*Change{gChanges.Foo.Update.copy(t)},
+		},
+		{
+			name:   "SAME column of the SAME entity with DIFF BYTES values",
+			source: []*Change{srcBytes},
+			target: []*Change{tgtBytes},
+			want:   []*Conflict{{Source: srcBytes, Target: tgtBytes}},
+		},
+		{
+			name:   "SAME column of the SAME entity with SAME BYTES values",
+			source: []*Change{srcBytes},
+			target: []*Change{bytesChg("foo")},
+		},
+		{
+			name:   "DIFF entities",
+			source: []*Change{gChanges.Foo.Update},
+			target: []*Change{gChanges.Bar.Update},
+		},
+		{
+			name:   "CREATES never conflict",
+			source: []*Change{gChanges.Foo.Create},
+			target: []*Change{diffCreate},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := conflicts(tt.source, tt.target)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("conflicts() mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
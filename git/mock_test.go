@@ -3,6 +3,10 @@ package git
 import (
 	"context"
 	"log"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
 
 	"github.com/sebach1/rtc/integrity"
 )
@@ -51,6 +55,43 @@ func (chg *Change) changeType(newType integrity.CRUD) *Change {
 	return chg
 }
 
+// blockingMock is a collaborator whose calls block until its ctx is done
+type blockingMock struct {
+	collabMock
+}
+
+func (mock *blockingMock) Create(ctx context.Context, comm *Commit) (*Commit, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// gaugeMock is a collaborator which measures the peak of goroutines alive and of its concurrent calls
+type gaugeMock struct {
+	collabMock
+
+	mu            sync.Mutex
+	calls         int
+	PeakCalls     int
+	PeakGoroutine int
+}
+
+func (mock *gaugeMock) Create(ctx context.Context, comm *Commit) (*Commit, error) {
+	mock.mu.Lock()
+	mock.calls++
+	if mock.calls > mock.PeakCalls {
+		mock.PeakCalls = mock.calls
+	}
+	if qt := runtime.NumGoroutine(); qt > mock.PeakGoroutine {
+		mock.PeakGoroutine = qt
+	}
+	mock.mu.Unlock()
+	time.Sleep(time.Microsecond) // As any remote call
+	mock.mu.Lock()
+	mock.calls--
+	mock.mu.Unlock()
+	return comm, nil
+}
+
 // Pushes a MOCKED COLLABORATOR with the ASSIGNED TABLE which RETURNS THE GIVEN ERROR
 func (pR *PullRequest) mock(tableName integrity.TableName, err error) *PullRequest {
 	pR.Team.mock(tableName, err)
@@ -66,3 +107,140 @@ func (t *Team) mock(tableName integrity.TableName, err error) *Team {
 	}
 	return t
 }
+
+// collabSpy is a collaborator which records the actions it performs and fails on the given call (1-indexed)
+// Notice its retrievals returns the asked columns valued, in order to be usable as pre-images
+type collabSpy struct {
+	FailAt int
+	Err    error
+
+	mu    sync.Mutex
+	Calls []integrity.CRUD
+	Keys  []string // Idempotency keys received by each call
+}
+
+func (spy *collabSpy) call(ctx context.Context, crud integrity.CRUD, comm *Commit) (*Commit, error) {
+	spy.mu.Lock()
+	defer spy.mu.Unlock()
+	spy.Calls = append(spy.Calls, crud)
+	key, _ := IdempotencyKeyFrom(ctx)
+	spy.Keys = append(spy.Keys, key)
+	if len(spy.Calls) == spy.FailAt {
+		return nil, spy.Err
+	}
+	return comm, nil
+}
+
+func (spy *collabSpy) Create(ctx context.Context, comm *Commit) (*Commit, error) {
+	return spy.call(ctx, "create", comm)
+}
+
+func (spy *collabSpy) Retrieve(ctx context.Context, comm *Commit) (*Commit, error) {
+	got, err := spy.call(ctx, "retrieve", comm)
+	if err != nil {
+		return nil, err
+	}
+	valued := &Commit{}
+	for _, chg := range got.Changes {
+		valued.Changes = append(valued.Changes, &Change{ColumnName: chg.ColumnName, StringValue: "spy", ValueType: "string"})
+	}
+	return valued, nil
+}
+
+func (spy *collabSpy) Update(ctx context.Context, comm *Commit) (*Commit, error) {
+	return spy.call(ctx, "update", comm)
+}
+
+func (spy *collabSpy) Delete(ctx context.Context, comm *Commit) (*Commit, error) {
+	return spy.call(ctx, "delete", comm)
+}
+
+func (spy *collabSpy) Init(ctx context.Context) error {
+	return nil
+}
+
+// preparerMock is a collaborator able to take part of a two-phase commit
+// It fails the preparation with the given PrepareErr, and the commit with the given CommitErr
+type preparerMock struct {
+	collabMock
+	PrepareErr error
+	CommitErr  error
+
+	mu                           sync.Mutex
+	Prepared, Committed, Aborted int
+}
+
+func (mock *preparerMock) Prepare(ctx context.Context, comm *Commit) error {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if mock.PrepareErr != nil {
+		return mock.PrepareErr
+	}
+	mock.Prepared++
+	return nil
+}
+
+func (mock *preparerMock) CommitPrepared(ctx context.Context, comm *Commit) (*Commit, error) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if mock.CommitErr != nil {
+		return nil, mock.CommitErr
+	}
+	mock.Committed++
+	return comm, nil
+}
+
+func (mock *preparerMock) Abort(ctx context.Context, comm *Commit) error {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.Aborted++
+	return nil
+}
+
+// ledgerMock is an in-memory Ledger
+type ledgerMock struct {
+	Err error
+	// RecordErrs are returned, in order, by the first calls to Record
+	RecordErrs []error
+
+	mu      sync.Mutex
+	Results map[string]*Result
+}
+
+func (l *ledgerMock) Applied(ctx context.Context, key string) (*Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.Err != nil {
+		return nil, l.Err
+	}
+	return l.Results[key], nil
+}
+
+func (l *ledgerMock) Record(ctx context.Context, res *Result) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.RecordErrs) > 0 {
+		err := l.RecordErrs[0]
+		l.RecordErrs = l.RecordErrs[1:]
+		return err
+	}
+	if l.Results == nil {
+		l.Results = make(map[string]*Result)
+	}
+	l.Results[res.IdempotencyKey] = res
+	return nil
+}
+
+// rendererMock is a collaborator spy able to render its requests
+type rendererMock struct {
+	collabSpy
+}
+
+func (mock *rendererMock) Render(ctx context.Context, comm *Commit) (*Request, error) {
+	commType, err := comm.Type()
+	if err != nil {
+		return nil, err
+	}
+	key, _ := IdempotencyKeyFrom(ctx)
+	return &Request{Method: commType.ToHTTPVerb(), URL: "foo", Header: http.Header{"Idempotency-Key": {key}}}, nil
+}
@@ -0,0 +1,33 @@
+package git
+
+// An OwnerOption customizes the Owner which performs an orchestration
+type OwnerOption func(*Owner)
+
+// WithSaga enables the saga mode of the owner. See Owner.Saga
+func WithSaga() OwnerOption {
+	return func(own *Owner) {
+		own.Saga = true
+	}
+}
+
+// WithListener adds the listener to the ones which observe the orchestration. See Owner.Listeners
+func WithListener(l Listener) OwnerOption {
+	return func(own *Owner) {
+		own.Listeners = append(own.Listeners, l)
+	}
+}
+
+// WithBroadcaster makes the owner publish the progress of the orchestration onto the broadcaster
+// The events are published onto the topic of the pull request (see PullRequestTopic) and the given ones
+func WithBroadcaster(b *Broadcaster, topics ...string) OwnerOption {
+	return WithListener(&topicsListener{broadcaster: b, topics: topics})
+}
+
+// WithWorkers sets the qt of commits the owner reviews and delivers concurrently
+// See Owner.ReviewWorkers and Owner.DeliveryWorkers
+func WithWorkers(review, delivery int) OwnerOption {
+	return func(own *Owner) {
+		own.ReviewWorkers = review
+		own.DeliveryWorkers = delivery
+	}
+}
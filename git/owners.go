@@ -17,8 +17,39 @@ type Owner struct {
 	Project *schema.Planisphere
 	Summary chan *Result
 
+	// Saga makes the owner merge the commits one by one, compensating the already merged
+	// ones in reverse order when any of them fails. See Commit.Compensate
+	Saga bool
+
+	// CapturePreImages makes the owner retrieve the state of the entities before
+	// updating or deleting them, in order to be able to revert the commits later
+	// Notice the commits whose pre-image couldn't be retrieved aren't delivered. See Commit.Inverse
+	CapturePreImages bool
+
+	// Ledger keeps the commits already applied, in order to skip them when an orchestration
+	// is re-run (e.g. after a crash). If it's nil, every commit is delivered
+	// See Commit.IdempotencyKey
+	Ledger Ledger
+
+	// ReviewWorkers and DeliveryWorkers are the qt of commits reviewed and delivered concurrently
+	// Zero means as many reviewers as CPUs, and defaultDeliveryWorkers deliverers
+	// See Member.MaxConcurrency in order to cap the deliveries to a single collaborator
+	ReviewWorkers   int
+	DeliveryWorkers int
+	limiter         limiter
+
 	Waiter *sync.WaitGroup
 	err    error
+
+	// mu guards the reports onto the .Summary, and overflow keeps the ones which didn't fit on it
+	// See Owner.report
+	mu       sync.Mutex
+	overflow []*Result
+
+	// Listeners observe the lifecycle of the orchestration. See Event
+	Listeners []Listener
+
+	pullRequestId int64
 }
 
 // NewOwner returns a new instance of Owner, with needed initialization and validation
@@ -46,10 +77,12 @@ func (own *Owner) Orchestrate(
 	pR *PullRequest,
 ) {
 	defer own.Waiter.Done()
+	own.pullRequestId = pR.Id
 	var err error
 	pR, err = own.Delegate(ctx, community, schName, pR)
 	if err != nil {
 		own.err = err
+		own.emit(&Event{Type: EventOrchestrationFailed, Error: err.Error()})
 		return
 	}
 	own.Waiter.Add(1)
@@ -57,45 +90,99 @@ func (own *Owner) Orchestrate(
 }
 
 // Delegate creates a PullRequest and assigns a reviewer to the given commit
+// In case no schName is given, the commits can span many schemas, and each of them is delegated
+// to the team of the schema of its table. See PullRequest.AssignTeams
 func (own *Owner) Delegate(
 	ctx context.Context,
 	community *Community,
 	schName integrity.SchemaName,
 	pR *PullRequest,
-) (*PullRequest, error) {
+) (_ *PullRequest, err error) {
+	own.emit(&Event{Type: EventDelegateStarted})
+	defer func() {
+		ev := &Event{Type: EventDelegateFinished}
+		if err != nil {
+			ev.Error = err.Error()
+		}
+		own.emit(ev)
+	}()
 
-	err := own.validate()
+	err = own.validate()
 	if err != nil {
 		return nil, err
 	}
 
-	sch, err := own.Project.GetSchemaFromName(schName)
+	schemas, err := own.assignTeams(community, schName, pR)
 	if err != nil {
 		return nil, err
 	}
 
-	var wg sync.WaitGroup
-
-	err = pR.AssignTeam(community, schName)
+	graph, err := pR.dependencies()
+	if err != nil {
+		return nil, err
+	}
+	_, err = pR.order(graph) // Rejects cyclic dependencies
 	if err != nil {
 		return nil, err
 	}
 
-	own.Summary = make(chan *Result, len(pR.Commits))
+	summarySize := len(pR.Commits)
+	if own.Saga { // Room enough for the compensations
+		summarySize *= 2
+	}
+	own.Summary = make(chan *Result, summarySize)
 
+	var wg sync.WaitGroup
 	wg.Add(len(pR.Commits))
-	for commIdx := range pR.Commits {
-		go own.ReviewPRCommit(sch, pR, commIdx, &wg)
-	}
+	forEach(own.reviewWorkers(), len(pR.Commits), func(commIdx int) {
+		own.ReviewPRCommit(schemas[commIdx], pR, commIdx, &wg)
+	})
 	wg.Wait()
 
 	return pR, nil
 }
 
+// assignTeams assigns the team(s) of the pull request, retrieving the schema of each of its commits
+func (own *Owner) assignTeams(
+	community *Community,
+	schName integrity.SchemaName,
+	pR *PullRequest,
+) ([]*schema.Schema, error) {
+	if schName == "" {
+		return pR.AssignTeams(community, own.Project)
+	}
+	sch, err := own.Project.GetSchemaFromName(schName)
+	if err != nil {
+		return nil, err
+	}
+	err = pR.AssignTeam(community, schName)
+	if err != nil {
+		return nil, err
+	}
+	schemas := make([]*schema.Schema, len(pR.Commits))
+	for i := range schemas {
+		schemas[i] = sch
+	}
+	return schemas, nil
+}
+
 // WaitAndClose will wait for the Owner WaitGroup to be done and close the Owner.Summary
 // It closes an orchestration (Owner.Orchestrate())
+// Notice the results which overflowed the .Summary are appended to it. See Owner.report
 func (own *Owner) WaitAndClose() error {
 	own.Waiter.Wait()
+	own.mu.Lock()
+	defer own.mu.Unlock()
+	if len(own.overflow) > 0 {
+		summary := make(chan *Result, len(own.Summary)+len(own.overflow))
+		for len(own.Summary) > 0 {
+			summary <- <-own.Summary
+		}
+		for _, res := range own.overflow {
+			summary <- res
+		}
+		own.Summary, own.overflow = summary, nil
+	}
 	if own.Summary != nil {
 		// The channel can be nil if the owner was errored before
 		// a merge / review
@@ -104,110 +191,183 @@ func (own *Owner) WaitAndClose() error {
 	return own.err
 }
 
+// report sends the result onto the .Summary
+// Notice it never blocks: the results which doesn't fit on it (or the ones reported before the summary
+// was opened) are kept aside, and flushed onto it by WaitAndClose
+func (own *Owner) report(res *Result) {
+	own.mu.Lock()
+	defer own.mu.Unlock()
+	select {
+	case own.Summary <- res:
+	default:
+		own.overflow = append(own.overflow, res)
+	}
+}
+
 // Merge performs the needed actions in order to merge the pullRequest
 func (own *Owner) Merge(ctx context.Context, pR *PullRequest) {
 	defer own.Waiter.Done()
-	for _, comm := range pR.Commits {
-		if comm.Errored {
-			continue // Skips validation errs
+	defer own.emit(&Event{Type: EventMergeDone})
+	if pR.canTwoPhase() {
+		own.mergeTwoPhase(ctx, pR)
+		return
+	}
+	if own.Saga {
+		own.mergeSaga(ctx, pR)
+		return
+	}
+	own.mergeDAG(ctx, pR, func(comm *Commit) error {
+		if comm.Is(Rejected) {
+			return nil // Skips validation errs
 		}
 
 		commType, err := comm.Type()
 		if err != nil {
-			own.Summary <- &Result{CommitId: comm.Id, Error: err}
-			continue
+			_ = comm.transition(Failed)
+			own.report(&Result{CommitId: comm.Id, Error: err})
+			return err
 		}
 
-		comm.Merged = true
-		own.Waiter.Add(1)
-		switch commType {
-		case "create":
-			go own.Create(ctx, comm)
-		case "retrieve":
-			go own.Retrieve(ctx, comm)
-		case "update":
-			go own.Update(ctx, comm)
-		case "delete":
-			go own.Delete(ctx, comm)
+		err = comm.transition(InFlight)
+		if err != nil {
+			own.report(&Result{CommitId: comm.Id, Error: err})
+			return err
 		}
-	}
+		res, err := own.deliver(ctx, comm, commType)
+		comm.settle(err)
+		own.report(res)
+		return err
+	})
 }
 
 // Create will orchestrate the creations of any collaborator
 func (own *Owner) Create(ctx context.Context, comm *Commit) (*Commit, error) {
 	defer own.Waiter.Done()
-	newComm := &Commit{}
-	*newComm = *comm
-	err := comm.Reviewer.Init(ctx)
+	res, err := own.deliver(ctx, comm, "create")
 	if err != nil {
-		own.Summary <- &Result{CommitId: comm.Id, Error: err}
+		own.report(res)
 		return comm, err
 	}
-	newComm, err = comm.Reviewer.Create(ctx, newComm)
-	if err != nil {
-		own.Summary <- &Result{CommitId: comm.Id, Error: err}
-		return comm, err
-	}
-	*comm = *newComm
 	return comm, nil
 }
 
 // Retrieve will orchestrate the fetches of any collaborator
 func (own *Owner) Retrieve(ctx context.Context, comm *Commit) (*Commit, error) {
 	defer own.Waiter.Done()
-	newComm := &Commit{}
-	*newComm = *comm
-	err := comm.Reviewer.Init(ctx)
+	res, err := own.deliver(ctx, comm, "retrieve")
 	if err != nil {
-		own.Summary <- &Result{CommitId: comm.Id, Error: err}
+		own.report(res)
 		return comm, err
 	}
-	newComm, err = comm.Reviewer.Retrieve(ctx, newComm)
-	if err != nil {
-		own.Summary <- &Result{CommitId: comm.Id, Error: err}
-		return comm, err
-	}
-	*comm = *newComm
 	return comm, nil
 }
 
 // Update will orchestrate the updations of any collaborator
 func (own *Owner) Update(ctx context.Context, comm *Commit) (*Commit, error) {
 	defer own.Waiter.Done()
-	newComm := &Commit{}
-	*newComm = *comm
-	err := comm.Reviewer.Init(ctx)
-	if err != nil {
-		own.Summary <- &Result{CommitId: comm.Id, Error: err}
-		return comm, err
-	}
-	newComm, err = comm.Reviewer.Update(ctx, newComm)
+	res, err := own.deliver(ctx, comm, "update")
 	if err != nil {
-		own.Summary <- &Result{CommitId: comm.Id, Error: err}
+		own.report(res)
 		return comm, err
 	}
-	*comm = *newComm
 	return comm, nil
 }
 
 // Delete will orchestrate the deletions of any collaborator
 func (own *Owner) Delete(ctx context.Context, comm *Commit) (*Commit, error) {
 	defer own.Waiter.Done()
-	newComm := &Commit{}
-	*newComm = *comm
-	err := comm.Reviewer.Init(ctx)
+	res, err := own.deliver(ctx, comm, "delete")
 	if err != nil {
-		own.Summary <- &Result{CommitId: comm.Id, Error: err}
+		own.report(res)
 		return comm, err
 	}
-	newComm, err = comm.Reviewer.Delete(ctx, newComm)
+	return comm, nil
+}
+
+// deliver performs the action of the given type through the commit reviewer, retrying it
+// as its .RetryPolicy says, and takes its result over the commit
+// The commits already applied by a previous orchestration are skipped, and the applied ones are
+// recorded. See Owner.Ledger
+// Notice the returned result describes the delivery even when it fails
+func (own *Owner) deliver(ctx context.Context, comm *Commit, commType integrity.CRUD) (*Result, error) {
+	res := newResult(comm)
+	res.IdempotencyKey = comm.IdempotencyKey()
+	applied, err := own.applied(ctx, comm, res.IdempotencyKey)
+	if err != nil || applied {
+		return res.finish(comm, nil, err), err
+	}
+	ctx = WithIdempotencyKey(ctx, res.IdempotencyKey)
+	own.emit(&Event{Type: EventCallStarted, CommitId: comm.Id})
+	defer func() { own.emit(resultEvent(EventCallFinished, res)) }()
+
+	var newComm *Commit
+	comm.Attempts = 0
+	for {
+		var attempts int
+		newComm, attempts, err = own.attempt(ctx, comm, commType)
+		comm.Attempts += attempts
+		if !comm.failOver(ctx, err) {
+			break
+		}
+		tableName, _ := comm.TableName()
+		own.emit(&Event{Type: EventReviewerAssigned, CommitId: comm.Id, TableName: tableName,
+			Collaborator: collaboratorName(comm.Reviewer)})
+	}
 	if err != nil {
-		own.Summary <- &Result{CommitId: comm.Id, Error: err}
-		return comm, err
+		return res.finish(comm, nil, err), err
 	}
-	*comm = *newComm
+	comm.absorb(newComm)
+	res.finish(comm, newComm, nil)
+	own.recordApplied(ctx, comm, res)
+	return res, nil
+}
 
-	return comm, nil
+// attempt delivers the commit through its reviewer, following the settings of its delegation
+func (own *Owner) attempt(ctx context.Context, comm *Commit, commType integrity.CRUD) (newComm *Commit, attempts int, err error) {
+	ctx, cancel := comm.withTimeout(ctx)
+	defer cancel()
+	release, err := own.limiter.acquire(ctx, comm)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer release()
+	err = comm.Breaker.do(func() (err error) {
+		attempts, err = comm.RetryPolicy.do(ctx, comm.RateLimits, func(ctx context.Context) error {
+			err := comm.Reviewer.Init(ctx)
+			if err != nil {
+				return err
+			}
+			newComm, err = own.call(ctx, comm, commType)
+			return err
+		})
+		return
+	})
+	return newComm, attempts, err
+}
+
+// call performs a single call of the action of the given type to the commit reviewer
+func (own *Owner) call(ctx context.Context, comm *Commit, commType integrity.CRUD) (*Commit, error) {
+	newComm := &Commit{}
+	*newComm = *comm
+	switch commType {
+	case "create":
+		return comm.Reviewer.Create(ctx, newComm)
+	case "retrieve":
+		return comm.Reviewer.Retrieve(ctx, newComm)
+	case "update", "delete":
+		if own.CapturePreImages && comm.PreImage == nil {
+			// A commit without pre-image would be irreversible, so it fails before its delivery
+			err := own.capturePreImage(ctx, comm)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if commType == "update" {
+			return comm.Reviewer.Update(ctx, newComm)
+		}
+		return comm.Reviewer.Delete(ctx, newComm)
+	}
+	return nil, commType.Validate()
 }
 
 // validate validates itself integrity to be able to perform orchestration & reviewing (owner)
@@ -228,13 +388,29 @@ func (own *Owner) ReviewPRCommit(sch *schema.Schema, pR *PullRequest, commIdx in
 	var reviewWg sync.WaitGroup
 
 	comm := pR.Commits[commIdx]
+	comm.restart()
+	_ = comm.transition(Reviewing)
+	own.emit(&Event{Type: EventReviewStarted, CommitId: comm.Id})
 	defer func() { // Yes. That's shouting for a refactor
 		if err != nil {
-			own.Summary <- &Result{CommitId: comm.Id, Error: err}
-			comm.Errored = true
+			res := &Result{CommitId: comm.Id, Error: err, ErrorClass: ErrorClassRejected}
+			if err == errCircuitOpen { // Not delegated, despite it passed the review
+				res.ErrorClass = ErrorClassCircuitOpen
+			}
+			own.report(res)
+			_ = comm.transition(Rejected)
+			own.emit(resultEvent(EventCommitRejected, res))
+			return
 		}
+		_ = comm.transition(Delegated)
+		own.emit(&Event{Type: EventCommitDelegated, CommitId: comm.Id})
 	}()
 
+	if sch == nil { // Its table doesn't belong to any schema of the project. See PullRequest.AssignTeams
+		err = errNoSchema
+		return
+	}
+
 	schErrCh := make(chan error, len(comm.Changes))
 	reviewWg.Add(len(comm.Changes))
 	for _, chg := range comm.Changes {
@@ -242,7 +418,8 @@ func (own *Owner) ReviewPRCommit(sch *schema.Schema, pR *PullRequest, commIdx in
 		if err != nil {
 			return
 		}
-		go sch.ValidateCtx(chg.TableName, chg.ColumnName, chg.Options.Keys(), chg.Value(),
+		// Performed sync, as the commits are already reviewed concurrently. See Owner.ReviewWorkers
+		sch.ValidateCtx(chg.TableName, chg.ColumnName, chg.Options.Keys(), chg.Value(),
 			own.Project, &reviewWg, schErrCh)
 	}
 
@@ -268,9 +445,12 @@ func (own *Owner) ReviewPRCommit(sch *schema.Schema, pR *PullRequest, commIdx in
 		return
 	}
 
-	reviewer, err := pR.Team.Delegate(tableName)
+	delegations, err := pR.teamOf(sch.Name).delegations(tableName, comm.Id)
 	if err != nil {
 		return
 	}
-	comm.Reviewer = reviewer
+	own.emit(&Event{Type: EventReviewerAssigned, CommitId: comm.Id, TableName: tableName,
+		Collaborator: collaboratorName(delegations[0].Collab)})
+	comm.delegateTo(delegations[0])
+	comm.Failover = delegations[1:]
 }
@@ -3,6 +3,7 @@ package git
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"testing"
 
@@ -113,7 +114,7 @@ func TestOwner_ReviewPRCommit(t *testing.T) {
 			wg.Wait()
 			var gotQtErr int
 			for _, comm := range tt.args.pR.Commits {
-				if comm.Errored {
+				if comm.Is(Rejected) {
 					gotQtErr++
 				}
 			}
@@ -214,6 +215,50 @@ func TestOwner_Orchestrate(t *testing.T) {
 			},
 			wantsErr: true,
 		},
+		{
+			name: "spanning MANY SCHEMAS",
+			own:  newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo, gSchemas.Bar}),
+			args: args{
+				ctx: context.Background(),
+				community: &Community{
+					gTeams.Foo.copy(t).mock(gTables.Foo.Name, nil),
+					gTeams.Bar.copy(t).mock(gTables.Bar.Name, nil),
+				},
+				pullRequest: &PullRequest{Commits: []*Commit{
+					{Changes: []*Change{gChanges.Foo.Create.copy(t)}},
+					{Changes: []*Change{gChanges.Bar.Create.copy(t)}},
+				}},
+			},
+			wantErr:       nil,
+			wantQtResErrs: 0,
+		},
+		{
+			name: "spanning many schemas but TABLE NOT IN PLANISPHERE",
+			own:  newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo}),
+			args: args{
+				ctx:       context.Background(),
+				community: &Community{gTeams.Foo.copy(t).mock(gTables.Foo.Name, nil)},
+				pullRequest: &PullRequest{Commits: []*Commit{
+					{Changes: []*Change{gChanges.Foo.Create.copy(t)}},
+					{Changes: []*Change{gChanges.Bar.Create.copy(t)}},
+				}},
+			},
+			wantErr:       nil,
+			wantQtResErrs: 1,
+		},
+		{
+			name: "spanning many schemas but TEAM NOT IN COMMUNITY",
+			own:  newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo, gSchemas.Bar}),
+			args: args{
+				ctx:       context.Background(),
+				community: &Community{gTeams.Foo.copy(t).mock(gTables.Foo.Name, nil)},
+				pullRequest: &PullRequest{Commits: []*Commit{
+					{Changes: []*Change{gChanges.Foo.Create.copy(t)}},
+					{Changes: []*Change{gChanges.Bar.Create.copy(t)}},
+				}},
+			},
+			wantsErr: true,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -351,3 +396,54 @@ func TestOwner_Orchestrate(t *testing.T) {
 // 		})
 // 	}
 // }
+
+// syntheticPullRequest retrieves a pull request of qt independent create commits over the foo table
+func syntheticPullRequest(qt int) *PullRequest {
+	pR := &PullRequest{Commits: make([]*Commit, qt)}
+	for i := range pR.Commits {
+		chg := *gChanges.Foo.Create
+		pR.Commits[i] = &Commit{Id: int64(i + 1), Changes: []*Change{&chg}}
+	}
+	return pR
+}
+
+// BenchmarkOwner_Orchestrate measures the orchestration bounded by the default worker pools, whose
+// peak of goroutines doesn't grow with the qt of commits
+// Notice the heap allocated per op is about the same as the unbounded baseline, as the work is the same
+func BenchmarkOwner_Orchestrate(b *testing.B) {
+	benchmarkOrchestrate(b, false)
+}
+
+// BenchmarkOwner_Orchestrate_unbounded is the baseline of BenchmarkOwner_Orchestrate, which spawns
+// a goroutine per commit, as there are as many workers as commits
+func BenchmarkOwner_Orchestrate_unbounded(b *testing.B) {
+	benchmarkOrchestrate(b, true)
+}
+
+func benchmarkOrchestrate(b *testing.B, unbounded bool) {
+	for _, qt := range []int{1000, 10000, 50000} {
+		b.Run(fmt.Sprintf("%d commits", qt), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				gauge := &gaugeMock{}
+				team := &Team{AssignedSchema: gSchemas.Foo.Name}
+				_ = team.AddMember(gTables.Foo.Name, gauge, true)
+				pR := syntheticPullRequest(qt)
+				own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+				if unbounded {
+					own.ReviewWorkers, own.DeliveryWorkers = qt, qt
+				}
+				b.StartTimer()
+
+				own.Waiter.Add(1)
+				go own.Orchestrate(context.Background(), &Community{team}, gSchemas.Foo.Name, pR)
+				err := own.WaitAndClose()
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.ReportMetric(float64(gauge.PeakGoroutine), "peak-goroutines")
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package git
+
+import (
+	"context"
+
+	"github.com/sebach1/rtc/integrity"
+)
+
+// A Plan describes the deliveries an orchestration would perform, following its dependency order
+type Plan struct {
+	Steps []*Step `json:"steps,omitempty"`
+}
+
+// A Step is the planned delivery of a commit
+type Step struct {
+	CommitId  int64               `json:"commit_id,omitempty"`
+	Type      integrity.CRUD      `json:"type,omitempty"`
+	TableName integrity.TableName `json:"table_name,omitempty"`
+
+	// Collaborator is the type of the reviewer the commit was delegated to
+	Collaborator string `json:"collaborator,omitempty"`
+	// Request is the call the reviewer would perform. It's only given by the Renderers
+	Request *Request `json:"request,omitempty"`
+
+	// DependsOn are the positions on the plan of the steps which must be performed before
+	DependsOn []int `json:"depends_on,omitempty"`
+
+	// Applied tells the commit would be skipped, as it was applied by a previous orchestration
+	// See Owner.Ledger
+	Applied bool `json:"applied,omitempty"`
+
+	// Errors are the reasons the commit would be rejected
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Plan delegates the pull request as an orchestration does, describing the deliveries it would perform
+// Notice no collaborator is called: only the Renderers are asked to describe its requests
+func (own *Owner) Plan(
+	ctx context.Context,
+	community *Community,
+	schName integrity.SchemaName,
+	pR *PullRequest,
+) (*Plan, error) {
+	pR, err := own.Delegate(ctx, community, schName, pR)
+	if err != nil {
+		return nil, err
+	}
+	_ = own.WaitAndClose() // Nothing to wait: it only closes the summary
+	errs := make(map[int64][]string)
+	for res := range own.Summary {
+		if res.Error != nil {
+			errs[res.CommitId] = append(errs[res.CommitId], res.Error.Error())
+		}
+	}
+
+	graph, err := pR.dependencies()
+	if err != nil {
+		return nil, err
+	}
+	sorted, err := pR.order(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[*Commit]int, len(sorted))
+	plan := &Plan{}
+	for i, comm := range sorted {
+		positions[comm] = i
+		step := &Step{CommitId: comm.Id}
+		for _, dep := range graph[comm] {
+			step.DependsOn = append(step.DependsOn, positions[dep])
+		}
+		if comm.Is(Rejected) {
+			step.Errors = errs[comm.Id]
+		} else {
+			err = own.planStep(ctx, comm, step)
+			if err != nil {
+				step.Errors = append(step.Errors, err.Error())
+			}
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+	return plan, nil
+}
+
+// planStep describes the delivery of the delegated commit onto the given step
+func (own *Owner) planStep(ctx context.Context, comm *Commit, step *Step) error {
+	var err error
+	step.Type, err = comm.Type()
+	if err != nil {
+		return err
+	}
+	step.TableName, err = comm.TableName()
+	if err != nil {
+		return err
+	}
+	step.Collaborator = collaboratorName(comm.Reviewer)
+
+	key := comm.IdempotencyKey()
+	if own.Ledger != nil && key != "" {
+		res, err := own.Ledger.Applied(ctx, key)
+		if err != nil {
+			return err
+		}
+		step.Applied = res != nil
+	}
+
+	renderer, ok := comm.Reviewer.(Renderer)
+	if !ok {
+		return nil
+	}
+	step.Request, err = renderer.Render(WithIdempotencyKey(ctx, key), comm)
+	return err
+}
@@ -0,0 +1,129 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/schema"
+)
+
+func TestOwner_Plan(t *testing.T) {
+	t.Parallel()
+	withEntity := func(chg *Change, id integrity.Id) *Change {
+		chg.EntityId = id
+		return chg
+	}
+	tests := []struct {
+		name         string
+		commits      []*Commit
+		applied      []int // indices of the commits already applied
+		wantCommits  []int64
+		wantDeps     [][]int
+		wantApplied  []bool
+		wantQtErrs   []int
+		wantRendered []bool
+		wantErr      error
+	}{
+		{
+			name: "steps FOLLOW the DEPENDENCY order",
+			commits: []*Commit{
+				{Id: 2, Changes: []*Change{withEntity(gChanges.Foo.Update.copy(t), CommitRef(1))}},
+				{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}},
+			},
+			wantCommits:  []int64{1, 2},
+			wantDeps:     [][]int{nil, {0}},
+			wantApplied:  []bool{false, false},
+			wantQtErrs:   []int{0, 0},
+			wantRendered: []bool{true, true},
+		},
+		{
+			name: "REJECTED commit is planned WITH its ERRORS",
+			commits: []*Commit{
+				{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}},
+				{Id: 2, Changes: []*Change{gChanges.Foo.Create.copy(t), gChanges.Foo.Update.copy(t)}},
+			},
+			wantCommits:  []int64{1, 2},
+			wantDeps:     [][]int{nil, nil},
+			wantApplied:  []bool{false, false},
+			wantQtErrs:   []int{0, 1},
+			wantRendered: []bool{true, false},
+		},
+		{
+			name: "ALREADY APPLIED commit is planned as applied",
+			commits: []*Commit{
+				{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}},
+			},
+			applied:      []int{0},
+			wantCommits:  []int64{1},
+			wantDeps:     [][]int{nil},
+			wantApplied:  []bool{true},
+			wantQtErrs:   []int{0},
+			wantRendered: []bool{true},
+		},
+		{
+			name: "reference to an UNKNOWN commit",
+			commits: []*Commit{
+				{Id: 2, Changes: []*Change{withEntity(gChanges.Foo.Update.copy(t), CommitRef(1))}},
+			},
+			wantErr: errUnknownDependency,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			renderer := &rendererMock{}
+			team := gTeams.Foo.copy(t)
+			err := team.AddMember(gTables.Foo.Name, renderer, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ledger := &ledgerMock{}
+			for _, i := range tt.applied {
+				_ = ledger.Record(context.Background(), &Result{IdempotencyKey: tt.commits[i].IdempotencyKey()})
+			}
+			own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+			own.Ledger = ledger
+
+			got, err := own.Plan(context.Background(), &Community{team}, gSchemas.Foo.Name, &PullRequest{Commits: tt.commits})
+			if err != tt.wantErr {
+				t.Fatalf("Owner.Plan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(renderer.Calls) != 0 {
+				t.Errorf("Owner.Plan() CALLED the collaborator: %v", renderer.Calls)
+			}
+			if err != nil {
+				return
+			}
+
+			var gotCommits []int64
+			var gotDeps [][]int
+			var gotApplied, gotRendered []bool
+			var gotQtErrs []int
+			for _, step := range got.Steps {
+				gotCommits = append(gotCommits, step.CommitId)
+				gotDeps = append(gotDeps, step.DependsOn)
+				gotApplied = append(gotApplied, step.Applied)
+				gotRendered = append(gotRendered, step.Request != nil)
+				gotQtErrs = append(gotQtErrs, len(step.Errors))
+			}
+			if diff := cmp.Diff(tt.wantCommits, gotCommits); diff != "" {
+				t.Errorf("Owner.Plan() commits mismatch (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantDeps, gotDeps); diff != "" {
+				t.Errorf("Owner.Plan() dependencies mismatch (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantApplied, gotApplied); diff != "" {
+				t.Errorf("Owner.Plan() applied mismatch (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRendered, gotRendered); diff != "" {
+				t.Errorf("Owner.Plan() rendered mismatch (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantQtErrs, gotQtErrs); diff != "" {
+				t.Errorf("Owner.Plan() errors qt mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// defaultDeliveryWorkers is the qt of commits delivered concurrently when the owner doesn't define it
+const defaultDeliveryWorkers = 32
+
+// reviewWorkers retrieves the qt of goroutines which review the commits. See Owner.ReviewWorkers
+func (own *Owner) reviewWorkers() int {
+	if own.ReviewWorkers > 0 {
+		return own.ReviewWorkers
+	}
+	return runtime.NumCPU()
+}
+
+// deliveryWorkers retrieves the qt of goroutines which deliver the commits. See Owner.DeliveryWorkers
+func (own *Owner) deliveryWorkers() int {
+	if own.DeliveryWorkers > 0 {
+		return own.DeliveryWorkers
+	}
+	return defaultDeliveryWorkers
+}
+
+// forEach performs the action over each index of [0, qt), by at most the given qt of workers
+func forEach(workers, qt int, action func(i int)) {
+	if workers > qt {
+		workers = qt
+	}
+	idxs := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range idxs {
+				action(i)
+			}
+		}()
+	}
+	for i := 0; i < qt; i++ {
+		idxs <- i
+	}
+	close(idxs)
+	wg.Wait()
+}
+
+// A limiter caps the concurrent deliveries to each collaborator. See Member.MaxConcurrency
+// Notice the cap of a collaborator is fixed by the first commit delivered to it
+type limiter struct {
+	mu   sync.Mutex
+	sems map[Collaborator]chan struct{}
+}
+
+// acquire waits until the reviewer of the commit can take one more delivery, and retrieves the func
+// which releases it
+// The collaborators which aren't comparable (e.g. non-pointers) can't be identified, so they aren't capped
+func (l *limiter) acquire(ctx context.Context, comm *Commit) (release func(), err error) {
+	release = func() {}
+	if comm.MaxConcurrency <= 0 || comm.Reviewer == nil || !reflect.TypeOf(comm.Reviewer).Comparable() {
+		return release, nil
+	}
+	l.mu.Lock()
+	if l.sems == nil {
+		l.sems = make(map[Collaborator]chan struct{})
+	}
+	sem, ok := l.sems[comm.Reviewer]
+	if !ok {
+		sem = make(chan struct{}, comm.MaxConcurrency)
+		l.sems[comm.Reviewer] = sem
+	}
+	l.mu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return release, ctx.Err()
+	}
+}
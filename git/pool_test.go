@@ -0,0 +1,80 @@
+package git
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/sebach1/rtc/schema"
+)
+
+func Test_forEach(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var running, peak int
+	done := make([]bool, 100)
+	forEach(3, len(done), func(i int) {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+		done[i] = true
+		mu.Lock()
+		running--
+		mu.Unlock()
+	})
+	for i, ok := range done {
+		if !ok {
+			t.Errorf("forEach() did NOT PERFORM the index %v", i)
+		}
+	}
+	if peak > 3 {
+		t.Errorf("forEach() performed %v actions concurrently, want at most %v", peak, 3)
+	}
+}
+
+func TestOwner_Orchestrate_concurrency(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name            string
+		deliveryWorkers int
+		maxConcurrency  int
+		wantPeak        int
+	}{
+		{name: "DELIVERIES are BOUNDED by the workers", deliveryWorkers: 4, wantPeak: 4},
+		{name: "COLLABORATOR cap is BELOW the workers", deliveryWorkers: 4, maxConcurrency: 2, wantPeak: 2},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gauge := &gaugeMock{}
+			team := &Team{AssignedSchema: gSchemas.Foo.Name}
+			err := team.AddMember(gTables.Foo.Name, gauge, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			team.Members[0].MaxConcurrency = tt.maxConcurrency
+			pR := syntheticPullRequest(200)
+
+			own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+			own.DeliveryWorkers = tt.deliveryWorkers
+			own.Waiter.Add(1)
+			go own.Orchestrate(context.Background(), &Community{team}, gSchemas.Foo.Name, pR)
+			err = own.WaitAndClose()
+			if err != nil {
+				t.Fatalf("Owner.Orchestrate() error = %v", err)
+			}
+			for _, comm := range pR.Commits {
+				if !comm.Is(Merged) {
+					t.Fatalf("Owner.Orchestrate() commit %v is %v, want %v", comm.Id, comm.State, Merged)
+				}
+			}
+			if gauge.PeakCalls > tt.wantPeak {
+				t.Errorf("Owner.Orchestrate() peak of concurrent calls = %v, want at most %v", gauge.PeakCalls, tt.wantPeak)
+			}
+		})
+	}
+}
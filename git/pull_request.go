@@ -2,12 +2,16 @@ package git
 
 import (
 	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/schema"
 )
 
 // A PullRequest connects a group of Commits with a team
+// Its commits can span many schemas, in which case each of them is delivered by the team of its own
+// schema. See PullRequest.AssignTeams
 type PullRequest struct {
 	Id      int64     `json:"id,omitempty"`
 	Team    *Team     `json:"team,omitempty"`
+	Teams   []*Team   `json:"teams,omitempty"`
 	Commits []*Commit `json:"commits,omitempty"`
 }
 
@@ -16,9 +20,9 @@ func NewPullRequest(commits []*Commit) *PullRequest {
 }
 
 // AssignTeam looks up for a team given a schemaName and a community
-// Notice that it cleans up the current Team
+// Notice that it cleans up the current Team and Teams
 func (pR *PullRequest) AssignTeam(community *Community, schName integrity.SchemaName) error {
-	pR.Team = &Team{}
+	pR.Team, pR.Teams = &Team{}, nil
 	team, err := community.LookFor(schName)
 	if err != nil {
 		return err
@@ -26,3 +30,42 @@ func (pR *PullRequest) AssignTeam(community *Community, schName integrity.Schema
 	pR.Team = team
 	return nil
 }
+
+// AssignTeams looks up for the team of the schema of each commit, which is resolved by its table
+// through the project, and retrieves the schemas by commit index. See Planisphere.SchemaOfTable
+// Notice the commits whose schema can't be resolved are left without schema, in order to be rejected
+// by its review, but the schemas without team err
+func (pR *PullRequest) AssignTeams(community *Community, project *schema.Planisphere) ([]*schema.Schema, error) {
+	pR.Team, pR.Teams = nil, nil
+	schemas := make([]*schema.Schema, len(pR.Commits))
+	for i, comm := range pR.Commits {
+		tableName, err := comm.TableName()
+		if err != nil {
+			continue
+		}
+		sch, err := project.SchemaOfTable(tableName)
+		if err != nil {
+			continue
+		}
+		schemas[i] = sch
+		if pR.teamOf(sch.Name) != nil {
+			continue
+		}
+		team, err := community.LookFor(sch.Name)
+		if err != nil {
+			return nil, err
+		}
+		pR.Teams = append(pR.Teams, team)
+	}
+	return schemas, nil
+}
+
+// teamOf retrieves the team assigned to the given schema, which is the .Team if it's the only one
+func (pR *PullRequest) teamOf(schName integrity.SchemaName) *Team {
+	for _, team := range pR.Teams {
+		if team.AssignedSchema == schName {
+			return team
+		}
+	}
+	return pR.Team
+}
@@ -0,0 +1,182 @@
+package git
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultRetryAfter is the wait before calling again a service which throttled a call without
+// telling for how long
+const defaultRetryAfter = time.Second
+
+// A RateLimit is a token bucket which throttles the calls to the collaborators
+// The calls exceeding it wait until they're allowed, instead of failing
+// Notice it keeps its state between orchestrations, so it must be shared by pointer
+type RateLimit struct {
+	// Rate is the qt of calls allowed per second, and Burst the qt of them which can be performed at once
+	// A zero rate doesn't limit the calls, which are only throttled by the services. See RateLimit.Pause
+	Rate  float64 `json:"rate,omitempty"`
+	Burst int     `json:"burst,omitempty"`
+
+	mu      sync.Mutex
+	started bool
+	tokens  float64
+	last    time.Time
+	paused  time.Time // No call is allowed until then
+}
+
+func (rl *RateLimit) burst() float64 {
+	if rl.Burst < 1 {
+		return 1
+	}
+	return float64(rl.Burst)
+}
+
+// refill adds the tokens earned since the last refill. It must be called with the lock held
+func (rl *RateLimit) refill(now time.Time) {
+	if !rl.started {
+		rl.tokens, rl.last, rl.started = rl.burst(), now, true
+		return
+	}
+	rl.tokens = math.Min(rl.burst(), rl.tokens+now.Sub(rl.last).Seconds()*rl.Rate)
+	rl.last = now
+}
+
+// reserve takes a token, and retrieves the wait until the call it allows can be performed
+func (rl *RateLimit) reserve(now time.Time) (wait time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if rl.Rate > 0 {
+		rl.refill(now)
+		rl.tokens--
+		if rl.tokens < 0 {
+			wait = time.Duration(-rl.tokens / rl.Rate * float64(time.Second))
+		}
+	}
+	if until := rl.paused.Sub(now); until > wait {
+		wait = until
+	}
+	return wait
+}
+
+// Wait blocks until a call is allowed, or the ctx is done
+// A nil rate limit allows every call
+func (rl *RateLimit) Wait(ctx context.Context) error {
+	if rl == nil {
+		return nil
+	}
+	wait := rl.reserve(time.Now())
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Pause disallows every call during the given duration (e.g. as a service asked by a Retry-After header)
+func (rl *RateLimit) Pause(d time.Duration) {
+	if rl == nil {
+		return
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if until := time.Now().Add(d); until.After(rl.paused) {
+		rl.paused = until
+	}
+}
+
+// Adapt takes the qt of calls the service still allows until the given reset (e.g. as told by
+// X-RateLimit-Remaining and X-RateLimit-Reset headers)
+// Once none remains, the calls are paused until the reset
+func (rl *RateLimit) Adapt(remaining int, reset time.Time) {
+	if rl == nil {
+		return
+	}
+	if remaining <= 0 {
+		rl.Pause(time.Until(reset))
+		return
+	}
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if rl.Rate > 0 {
+		rl.refill(time.Now())
+		rl.tokens = math.Min(rl.tokens, float64(remaining))
+	}
+}
+
+type rateLimitsCtxKey struct{}
+
+// WithRateLimits returns a copy of the ctx which carries the rate limits the call is throttled by
+func WithRateLimits(ctx context.Context, limits ...*RateLimit) context.Context {
+	return context.WithValue(ctx, rateLimitsCtxKey{}, limits)
+}
+
+// RateLimitsFrom retrieves the rate limits carried by the ctx. See Team.delegation
+// Collaborators should adapt them to the limits its services tell. See RateLimit.Adapt
+func RateLimitsFrom(ctx context.Context) []*RateLimit {
+	limits, _ := ctx.Value(rateLimitsCtxKey{}).([]*RateLimit)
+	return limits
+}
+
+// A ThrottledError is the error of a call which may be rejected by the rate limits of the service
+// Throttled tells if that's the case, and the wait the service asks for before calling it again
+type ThrottledError interface {
+	error
+	Throttled() (retryAfter time.Duration, ok bool)
+}
+
+// throttledBy retrieves the wait asked by the service which throttled the call, if it's the case
+func throttledBy(err error) (time.Duration, bool) {
+	throttled, ok := errors.Cause(err).(ThrottledError)
+	if !ok {
+		return 0, false
+	}
+	retryAfter, ok := throttled.Throttled()
+	if !ok {
+		return 0, false
+	}
+	if retryAfter <= 0 {
+		retryAfter = defaultRetryAfter
+	}
+	return retryAfter, true
+}
+
+// waitRateLimits blocks until all the given rate limits allow a call, or the ctx is done
+func waitRateLimits(ctx context.Context, limits []*RateLimit) error {
+	for _, rl := range limits {
+		err := rl.Wait(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pauseRateLimits pauses the given rate limits during the wait asked by the service which throttled
+// a call, or blocks during it if there are no rate limits
+func pauseRateLimits(ctx context.Context, limits []*RateLimit, retryAfter time.Duration) error {
+	if len(limits) == 0 {
+		timer := time.NewTimer(retryAfter)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+	for _, rl := range limits {
+		rl.Pause(retryAfter)
+	}
+	return nil
+}
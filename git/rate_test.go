@@ -0,0 +1,95 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// throttledErr is a ThrottledError which asks for the given wait
+type throttledErr time.Duration
+
+func (err throttledErr) Error() string                    { return "throttled" }
+func (err throttledErr) Throttled() (time.Duration, bool) { return time.Duration(err), true }
+
+func TestRateLimit_reserve(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	rl := &RateLimit{Rate: 10, Burst: 2}
+	for i := 0; i < 2; i++ {
+		if wait := rl.reserve(now); wait != 0 {
+			t.Errorf("RateLimit.reserve() call %v within the burst waits %v", i, wait)
+		}
+	}
+	if wait := rl.reserve(now); wait != 100*time.Millisecond {
+		t.Errorf("RateLimit.reserve() exceeding the burst waits %v, want %v", wait, 100*time.Millisecond)
+	}
+	if wait := rl.reserve(now.Add(time.Second)); wait != 0 { // Refilled
+		t.Errorf("RateLimit.reserve() once refilled waits %v", wait)
+	}
+
+	rl.Adapt(0, now.Add(time.Hour))
+	if wait := rl.reserve(time.Now()); wait < 59*time.Minute {
+		t.Errorf("RateLimit.reserve() without remaining calls waits %v, want until the reset", wait)
+	}
+
+	unlimited := &RateLimit{}
+	unlimited.Pause(time.Minute)
+	if wait := unlimited.reserve(time.Now()); wait < 59*time.Second {
+		t.Errorf("RateLimit.reserve() once paused waits %v, want %v", wait, time.Minute)
+	}
+}
+
+func TestRateLimit_Wait(t *testing.T) {
+	t.Parallel()
+	var rl *RateLimit
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Errorf("RateLimit.Wait() of a NIL rate limit error = %v", err)
+	}
+
+	rl = &RateLimit{}
+	rl.Pause(time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := rl.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("RateLimit.Wait() error = %v, wantErr %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRetryPolicy_do_throttled(t *testing.T) {
+	t.Parallel()
+	rl := &RateLimit{}
+	var calls int
+	gotAttempts, err := (*RetryPolicy)(nil).do(context.Background(), []*RateLimit{rl}, func(ctx context.Context) error {
+		calls++
+		if limits := RateLimitsFrom(ctx); len(limits) != 1 || limits[0] != rl {
+			t.Errorf("RetryPolicy.do() call WITHOUT its rate limits: %v", limits)
+		}
+		if calls < 3 {
+			return throttledErr(time.Millisecond)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("RetryPolicy.do() error = %v", err)
+	}
+	if calls != 3 || gotAttempts != 1 {
+		t.Errorf("RetryPolicy.do() calls = %v, attempts = %v; want 3 calls of a single attempt", calls, gotAttempts)
+	}
+}
+
+func TestRetryPolicy_do_throttledForever(t *testing.T) {
+	t.Parallel()
+	var calls int
+	p := &RetryPolicy{MaxAttempts: 2, MaxThrottled: 3}
+	gotAttempts, err := p.do(context.Background(), nil, func(ctx context.Context) error {
+		calls++
+		return throttledErr(time.Millisecond)
+	})
+	if err != throttledErr(time.Millisecond) {
+		t.Errorf("RetryPolicy.do() error = %v, wantErr %v", err, throttledErr(time.Millisecond))
+	}
+	if calls != 4 || gotAttempts != 1 {
+		t.Errorf("RetryPolicy.do() calls = %v, attempts = %v; want 4 calls of a single attempt", calls, gotAttempts)
+	}
+}
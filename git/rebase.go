@@ -0,0 +1,242 @@
+package git
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/internal/store"
+	"github.com/sebach1/rtc/schema"
+)
+
+// pick retrieves a fresh copy of the change, ready to be added onto another index
+func (chg *Change) pick(indexId int64) *Change {
+	picked := *chg
+	picked.Id, picked.CommitId, picked.IndexId = 0, 0, indexId
+	if chg.BytesValue != nil {
+		picked.BytesValue = append([]byte(nil), chg.BytesValue...)
+	}
+	return &picked
+}
+
+// CherryPick copies the changes of the given commits of the source branch onto the index of the target one
+// In case no commit is given, the unmerged commits of the source are picked. See Branch.UnmergedCommits
+// Notice the copies get fresh ids, and they're validated against the target branch and its index as any
+// added change, discarding the ones already on it. See Add
+func CherryPick(
+	ctx context.Context,
+	db *sqlx.DB,
+	project *schema.Planisphere,
+	sourceName integrity.BranchName,
+	targetName integrity.BranchName,
+	commitIds ...int64,
+) ([]*Change, error) {
+	if sourceName == targetName {
+		return nil, errSameBranch
+	}
+	source, err := BranchByName(ctx, db, sourceName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find source branch by name")
+	}
+	target, err := BranchByName(ctx, db, targetName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find target branch by name")
+	}
+	err = target.FetchIndex(ctx, db)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch index")
+	}
+	err = target.Index.FetchUncommittedChanges(ctx, db)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch uncommitted changes")
+	}
+
+	var comms []*Commit
+	if len(commitIds) == 0 {
+		comms, err = source.UnmergedCommits(ctx, db)
+		if err != nil {
+			return nil, errors.Wrap(err, "branch fetch unmerged commits")
+		}
+	}
+	for _, id := range commitIds {
+		comm, err := CommitById(ctx, db, id)
+		if err != nil {
+			return nil, errors.Wrap(err, "find commit by id")
+		}
+		if comm.BranchId != source.Id {
+			return nil, errForeignCommit
+		}
+		comms = append(comms, comm)
+	}
+	err = fetchCommitsChanges(ctx, db, comms)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch commits changes")
+	}
+	resolved, err := source.TemporaryIds(ctx, db)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch source temporary ids")
+	}
+
+	var picked []*Change
+	var batch []store.Storable
+	for _, comm := range comms {
+		for _, chg := range comm.Changes {
+			chg = chg.pick(target.IndexId)
+			chg.resolveTemporaryIds(resolved) // Only known by the source
+			err = target.validate(project, "", chg)
+			if err != nil {
+				return nil, errors.Wrap(err, "validate change")
+			}
+			if target.Index.containsChange(chg) {
+				continue
+			}
+			err = target.Index.add(chg)
+			if err != nil {
+				return nil, errors.Wrap(err, "index add change")
+			}
+			picked = append(picked, chg)
+			batch = append(batch, chg)
+		}
+	}
+	if len(batch) == 0 {
+		return picked, nil
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer tx.Rollback() // No-op once committed
+	err = store.InsertIntoDB(ctx, tx, batch...)
+	if err != nil {
+		return nil, errors.Wrap(err, "insert picked changes into db")
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "commit tx")
+	}
+	return picked, nil
+}
+
+// latestChanges retrieves the last change of the given commits which modifies each column of each entity
+// Notice the commits are expected to be ordered by its ids
+func latestChanges(comms []*Commit) (latest []*Change) {
+	for _, comm := range comms {
+		for _, chg := range comm.Changes {
+			for i, otherChg := range latest {
+				if Overrides(chg, otherChg) {
+					latest = append(latest[:i], latest[i+1:]...)
+					break
+				}
+			}
+			latest = append(latest, chg)
+		}
+	}
+	return
+}
+
+// replay resolves the given commits on top of the merged ones, retrieving the changes which became
+// no-ops, as the merged commits already left its column with the same value, and the commits which
+// became empty after dropping them
+func replay(comms, merged []*Commit, resolved map[integrity.Id]integrity.Id) (noOps []*Change, emptied []*Commit) {
+	latest := latestChanges(merged)
+	for _, comm := range comms {
+		var kept []*Change
+		for _, chg := range comm.Changes {
+			chg.resolveTemporaryIds(resolved)
+			if (&Index{Changes: latest}).containsChange(chg) {
+				noOps = append(noOps, chg)
+				continue
+			}
+			kept = append(kept, chg)
+		}
+		comm.Changes = kept
+		if len(kept) == 0 {
+			emptied = append(emptied, comm)
+		}
+	}
+	return
+}
+
+// Rebase replays the pending commits of the branch on top of the commits merged by the onto one,
+// retrieving the replayed commits
+// Notice the changes which became no-ops are dropped, and so are the commits which became empty
+func Rebase(
+	ctx context.Context,
+	db *sqlx.DB,
+	branchName integrity.BranchName,
+	ontoName integrity.BranchName,
+) ([]*Commit, error) {
+	if branchName == ontoName {
+		return nil, errSameBranch
+	}
+	branch, err := BranchByName(ctx, db, branchName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find branch by name")
+	}
+	onto, err := BranchByName(ctx, db, ontoName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find onto branch by name")
+	}
+	comms, err := branch.CommitsByState(ctx, db, mergeableStates...)
+	if err != nil {
+		return nil, errors.Wrap(err, "branch fetch pending commits")
+	}
+	merged, err := onto.CommitsByState(ctx, db, Merged)
+	if err != nil {
+		return nil, errors.Wrap(err, "onto fetch merged commits")
+	}
+	err = fetchCommitsChanges(ctx, db, comms)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch commits changes")
+	}
+	err = fetchCommitsChanges(ctx, db, merged)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch merged commits changes")
+	}
+	resolved, err := onto.TemporaryIds(ctx, db)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch onto temporary ids")
+	}
+
+	noOps, emptied := replay(comms, merged, resolved)
+	var replayed []*Commit
+	var updated []store.Storable
+	for _, comm := range comms {
+		if len(comm.Changes) == 0 {
+			continue
+		}
+		for _, chg := range comm.Changes {
+			updated = append(updated, chg)
+		}
+		replayed = append(replayed, comm)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer tx.Rollback() // No-op once committed
+	for _, chg := range noOps {
+		err = store.DeleteFromDB(ctx, tx, chg)
+		if err != nil {
+			return nil, errors.Wrap(err, "delete no-op change from db")
+		}
+	}
+	err = store.UpdateIntoDB(ctx, tx, updated...)
+	if err != nil {
+		return nil, errors.Wrap(err, "update replayed changes into db")
+	}
+	for _, comm := range emptied {
+		err = deleteCommit(ctx, tx, comm)
+		if err != nil {
+			return nil, errors.Wrap(err, "delete empty commit from db")
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "commit tx")
+	}
+	return replayed, nil
+}
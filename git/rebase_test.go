@@ -0,0 +1,89 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/integrity"
+)
+
+func Test_replay(t *testing.T) {
+	t.Parallel()
+	withValue := func(chg *Change, val interface{}) *Change {
+		err := chg.SetValue(val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return chg
+	}
+	merged := func(chg *Change) *Change {
+		chg.Id = 0 // Its id differs from the ones of the pending changes
+		return chg
+	}
+	withEntity := func(chg *Change, id integrity.Id) *Change {
+		chg.EntityId = id
+		return chg
+	}
+	tests := []struct {
+		name        string
+		comms       []*Commit
+		merged      []*Commit
+		resolved    map[integrity.Id]integrity.Id
+		wantChanges [][]*Change
+		wantNoOps   int
+		wantEmptied int
+	}{
+		{
+			name:        "change ALREADY MERGED is DROPPED",
+			comms:       []*Commit{{Id: 1, Changes: []*Change{gChanges.Foo.Update.copy(t)}}},
+			merged:      []*Commit{{Id: 2, Changes: []*Change{merged(gChanges.Foo.Update.copy(t))}}},
+			wantChanges: [][]*Change{nil},
+			wantNoOps:   1,
+			wantEmptied: 1,
+		},
+		{
+			name:  "change merged but OVERRIDDEN LATER is KEPT",
+			comms: []*Commit{{Id: 1, Changes: []*Change{gChanges.Foo.Update.copy(t)}}},
+			merged: []*Commit{
+				{Id: 2, Changes: []*Change{merged(gChanges.Foo.Update.copy(t))}},
+				{Id: 3, Changes: []*Change{merged(withValue(gChanges.Foo.Update.copy(t), "diffFooValue"))}},
+			},
+			wantChanges: [][]*Change{{gChanges.Foo.Update}},
+		},
+		{
+			name: "only the no-ops of a commit are dropped",
+			comms: []*Commit{
+				{Id: 1, Changes: []*Change{gChanges.Foo.Update.copy(t), gChanges.Bar.Update.copy(t)}},
+			},
+			merged:      []*Commit{{Id: 2, Changes: []*Change{merged(gChanges.Bar.Update.copy(t))}}},
+			wantChanges: [][]*Change{{gChanges.Foo.Update}},
+			wantNoOps:   1,
+		},
+		{
+			name:        "TEMPORARY ids are RESOLVED by the merged ones",
+			comms:       []*Commit{{Id: 1, Changes: []*Change{withEntity(gChanges.Foo.Update.copy(t), "tmp:foo")}}},
+			resolved:    map[integrity.Id]integrity.Id{"tmp:foo": gChanges.Foo.Update.EntityId},
+			wantChanges: [][]*Change{{gChanges.Foo.Update}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			noOps, emptied := replay(tt.comms, tt.merged, tt.resolved)
+			if len(noOps) != tt.wantNoOps {
+				t.Errorf("replay() no-ops = %v, want %v", len(noOps), tt.wantNoOps)
+			}
+			if len(emptied) != tt.wantEmptied {
+				t.Errorf("replay() emptied = %v, want %v", len(emptied), tt.wantEmptied)
+			}
+			var got [][]*Change
+			for _, comm := range tt.comms {
+				got = append(got, comm.Changes)
+			}
+			if diff := cmp.Diff(tt.wantChanges, got); diff != "" {
+				t.Errorf("replay() changes mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
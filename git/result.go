@@ -1,7 +1,166 @@
 package git
 
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/internal/store"
+	"github.com/sebach1/rtc/internal/xerrors"
+)
+
 // Result is a commitment result
 type Result struct {
-	CommitId int64 `json:"commit_id,omitempty"`
-	Error    error `json:"error,omitempty"`
+	Id            int64 `json:"id,omitempty"`
+	CommitId      int64 `json:"commit_id,omitempty"`
+	PullRequestId int64 `json:"pull_request_id,omitempty"`
+
+	// Error is the cause of the failure of the commitment, which is described by the
+	// .ErrorClass and .ErrorMessage once persisted. See Result.describe
+	Error        error      `json:"-"`
+	ErrorClass   ErrorClass `json:"error_class,omitempty"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+
+	// Collaborator is the type of the reviewer the commit was delivered to
+	Collaborator string `json:"collaborator,omitempty"`
+	// Payload is the commit returned by the collaborator, encoded as JSON
+	Payload *json.RawMessage `json:"payload,omitempty"`
+
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	// IdempotencyKey is the key of the delivered commit. See Commit.IdempotencyKey
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// EntityId is the id of the entity the commit was applied to
+	EntityId integrity.Id `json:"entity_id,omitempty"`
+
+	// Attempts is the qt of calls performed to the collaborator. See RetryPolicy
+	// Notice it's zero when the commit was skipped as it was already applied. See Owner.Ledger
+	Attempts int `json:"attempts,omitempty"`
+
+	// Compensation tells if the result is of a compensating action. See Owner.Saga
+	Compensation bool `json:"compensation,omitempty"`
+}
+
+// An ErrorClass is the kind of failure of a commitment
+type ErrorClass string
+
+const (
+	// ErrorClassRejected is the class of the commits which didn't pass its review
+	ErrorClassRejected ErrorClass = "rejected"
+	// ErrorClassDependency is the class of the commits whose dependencies weren't merged
+	ErrorClassDependency ErrorClass = "dependency"
+	// ErrorClassAborted is the class of the commits aborted by the failure of others. See Owner.Saga
+	ErrorClassAborted ErrorClass = "aborted"
+	// ErrorClassTimeout is the class of the deliveries which exceeded its deadline or were canceled
+	ErrorClassTimeout ErrorClass = "timeout"
+	// ErrorClassTransient is the class of the deliveries which failed after exhausting its retries
+	ErrorClassTransient ErrorClass = "transient"
+	// ErrorClassPermanent is the class of the deliveries which failed in a non-retryable way
+	ErrorClassPermanent ErrorClass = "permanent"
+	// ErrorClassCircuitOpen is the class of the commits which failed fast, as the circuit breaker of its
+	// member was open. See Member.Breaker
+	ErrorClassCircuitOpen ErrorClass = "circuit_open"
+)
+
+// classify retrieves the class of the given error
+func classify(err error) ErrorClass {
+	if mErr, ok := err.(xerrors.MultiErr); ok && len(mErr) > 0 {
+		err = mErr[0]
+	}
+	switch errors.Cause(err) {
+	case nil:
+		return ""
+	case errFailedDependency:
+		return ErrorClassDependency
+	case errSagaAborted, errTwoPhaseAborted:
+		return ErrorClassAborted
+	case context.DeadlineExceeded, context.Canceled:
+		return ErrorClassTimeout
+	case errCircuitOpen:
+		return ErrorClassCircuitOpen
+	}
+	if IsRetryable(err) {
+		return ErrorClassTransient
+	}
+	return ErrorClassPermanent
+}
+
+// describe assigns the class and message of its .Error, in case they weren't assigned yet
+func (res *Result) describe() {
+	if res.Error == nil {
+		return
+	}
+	if res.ErrorClass == "" {
+		res.ErrorClass = classify(res.Error)
+	}
+	if res.ErrorMessage == "" {
+		res.ErrorMessage = res.Error.Error()
+	}
+}
+
+// resultOf retrieves the result of the delivery of the commit, which ended with the given err
+func resultOf(comm *Commit, err error) *Result {
+	return &Result{CommitId: comm.Id, Error: err, Attempts: comm.Attempts, Collaborator: collaboratorName(comm.Reviewer)}
+}
+
+// newResult starts the result of the delivery of the commit
+func newResult(comm *Commit) *Result {
+	res := resultOf(comm, nil)
+	now := time.Now()
+	res.StartedAt = &now
+	return res
+}
+
+// finish ends the result of the delivery of the commit, which returned the given commit or failed with err
+func (res *Result) finish(comm, returned *Commit, err error) *Result {
+	now := time.Now()
+	res.FinishedAt = &now
+	res.Attempts = comm.Attempts
+	res.Collaborator = collaboratorName(comm.Reviewer) // It may have failed over. See Commit.Failover
+	res.Error = err
+	if err != nil {
+		return res
+	}
+	res.EntityId = comm.entityId()
+	if returned != nil {
+		payload := *returned
+		payload.Reviewer = nil
+		raw, err := json.Marshal(&payload)
+		if err == nil { // Best-effort: the payload is only informative
+			res.Payload = (*json.RawMessage)(&raw)
+		}
+	}
+	return res
+}
+
+// storeResults persists the given results of the orchestration of the pull request
+// Notice the ones already recorded by the Ledger are skipped
+func storeResults(ctx context.Context, db *sqlx.DB, pR *PullRequest, results []*Result) error {
+	var storables []store.Storable
+	for _, res := range results {
+		if res.Id != 0 {
+			continue
+		}
+		res.PullRequestId = pR.Id
+		res.describe()
+		storables = append(storables, res)
+	}
+	if len(storables) == 0 {
+		return nil
+	}
+	return store.InsertIntoDB(ctx, db, storables...)
+}
+
+// ResultsByPullRequest retrieves the persisted results of the orchestration of the given pull request
+func ResultsByPullRequest(ctx context.Context, db *sqlx.DB, pullRequestId int64) ([]*Result, error) {
+	var results []*Result
+	err := db.SelectContext(ctx, &results, `SELECT * FROM results WHERE pull_request_id=? ORDER BY id`, pullRequestId)
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
 }
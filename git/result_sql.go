@@ -0,0 +1,41 @@
+package git
+
+// GetId wraps the id retrieval to implement Storable interface
+func (res *Result) GetId() int64 {
+	return res.Id
+}
+
+// SetId wraps the id assignation to implement Storable interface
+func (res *Result) SetId(id int64) {
+	res.Id = id
+}
+
+// SQLTable returns the sql SQLTable name of the entity
+//
+// Testing: tested by using naming conventions. See internal/name pkg
+func (res *Result) SQLTable() string {
+	return "results"
+}
+
+// SQLColumns returns the SQLColumns each field represent on db
+// Notice the returned slice is the list of struct tags of exported fields
+// It's done to avoid reflection
+//
+// Testing: tested by using reflection at Columns_Test to check being the tags
+func (res *Result) SQLColumns() []string {
+	return []string{
+		"id",
+		"commit_id",
+		"pull_request_id",
+		"error_class",
+		"error_message",
+		"collaborator",
+		"payload",
+		"started_at",
+		"finished_at",
+		"idempotency_key",
+		"entity_id",
+		"attempts",
+		"compensation",
+	}
+}
@@ -0,0 +1,43 @@
+package git
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gedex/inflector"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/internal/name"
+)
+
+func TestResultSQLColumns(t *testing.T) {
+	res := Result{}
+	exclusions := []string{"Error"}
+	typeOf := reflect.TypeOf(res)
+	var want []string
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		if isExcluded(exclusions, field.Name) {
+			continue
+		}
+		col := name.ToSnakeCase(field.Name)
+		want = append(want, col)
+	}
+	sort.Strings(want)
+
+	got := res.SQLColumns()
+	sort.Strings(got)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Result.SQLColumns() mismatch (-want +got): %s", diff)
+	}
+}
+
+func TestResultSQLTable(t *testing.T) {
+	res := Result{}
+	typeOf := reflect.TypeOf(res)
+	want := inflector.Pluralize(name.ToSnakeCase(typeOf.Name()))
+	got := res.SQLTable()
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Result.SQLTable() mismatch (-want +got): %s", diff)
+	}
+}
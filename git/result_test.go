@@ -0,0 +1,110 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/internal/test/assist"
+	"github.com/sebach1/rtc/internal/test/thelper"
+	"github.com/sebach1/rtc/internal/xerrors"
+)
+
+func Test_classify(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{name: "NIL error", err: nil, want: ""},
+		{name: "FAILED DEPENDENCY", err: errFailedDependency, want: ErrorClassDependency},
+		{name: "ABORTED two-phase", err: xerrors.NewMultiErr(errTwoPhaseAborted, errFoo), want: ErrorClassAborted},
+		{name: "ABORTED saga", err: errSagaAborted, want: ErrorClassAborted},
+		{name: "DEADLINE exceeded", err: errors.Wrap(context.DeadlineExceeded, "foo"), want: ErrorClassTimeout},
+		{name: "RETRYABLE error", err: retryableErr(true), want: ErrorClassTransient},
+		{name: "NON-RETRYABLE error", err: errFoo, want: ErrorClassPermanent},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := classify(tt.err); got != tt.want {
+				t.Errorf("classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_finish(t *testing.T) {
+	t.Parallel()
+	comm := &Commit{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}, Reviewer: &collabMock{}, Attempts: 2}
+	comm.Changes[0].EntityId = "fooCreatedId"
+
+	res := newResult(comm).finish(comm, comm, nil)
+	if res.StartedAt == nil || res.FinishedAt == nil || res.FinishedAt.Before(*res.StartedAt) {
+		t.Errorf("Result.finish() INCONSISTENT timestamps: started %v, finished %v", res.StartedAt, res.FinishedAt)
+	}
+	want := &Result{CommitId: 1, Collaborator: "*git.collabMock", EntityId: "fooCreatedId", Attempts: 2}
+	got := *res
+	got.StartedAt, got.FinishedAt, got.Payload = nil, nil, nil
+	if diff := cmp.Diff(want, &got); diff != "" {
+		t.Errorf("Result.finish() mismatch (-want +got): %s", diff)
+	}
+	if res.Payload == nil || len(*res.Payload) == 0 {
+		t.Errorf("Result.finish() WITHOUT payload")
+	}
+
+	res = newResult(comm).finish(comm, nil, errFoo)
+	res.describe()
+	if res.ErrorClass != ErrorClassPermanent || res.ErrorMessage != errFoo.Error() || res.Payload != nil {
+		t.Errorf("Result.finish() failure described as %v: %v (payload %v)", res.ErrorClass, res.ErrorMessage, res.Payload)
+	}
+}
+
+func TestResultsByPullRequest(t *testing.T) {
+	payload := json.RawMessage(`{"id":2}`)
+	resCols := []string{"id", "commit_id", "pull_request_id", "error_class", "error_message", "collaborator", "payload"}
+	tests := []struct {
+		name    string
+		stub    *assist.QueryStubber
+		want    []*Result
+		wantErr error
+	}{
+		{
+			name: "retrieves the results of the pull request",
+			stub: &assist.QueryStubber{
+				Expect: "SELECT * FROM results WHERE pull_request_id=?",
+				Rows: sqlmock.NewRows(resCols).
+					AddRow(1, 2, 3, "", "", "*foo.bar", []byte(`{"id":2}`)).
+					AddRow(4, 5, 3, ErrorClassPermanent, "foo", "*foo.bar", nil),
+			},
+			want: []*Result{
+				{Id: 1, CommitId: 2, PullRequestId: 3, Collaborator: "*foo.bar", Payload: &payload},
+				{Id: 4, CommitId: 5, PullRequestId: 3, Collaborator: "*foo.bar", ErrorClass: ErrorClassPermanent, ErrorMessage: "foo"},
+			},
+		},
+		{
+			name:    "query returns ERR",
+			stub:    &assist.QueryStubber{Expect: "SELECT * FROM results", Err: errFoo},
+			wantErr: errFoo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := thelper.MockDB(t)
+			tt.stub.Stub(mock)
+			got, err := ResultsByPullRequest(context.Background(), db, 3)
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("ResultsByPullRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ResultsByPullRequest() mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package git
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// A RetryPolicy describes how the calls to a collaborator are retried once they fail
+// A nil policy performs a single attempt
+type RetryPolicy struct {
+	// MaxAttempts is the qt of calls performed before giving up, including the first one
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// InitialBackoff is the wait before the second attempt, which is multiplied by the
+	// Multiplier on each of the following ones, up to MaxBackoff
+	InitialBackoff time.Duration `json:"initial_backoff,omitempty"`
+	MaxBackoff     time.Duration `json:"max_backoff,omitempty"`
+	Multiplier     float64       `json:"multiplier,omitempty"`
+
+	// Jitter is the fraction (from 0 to 1) of each backoff which is randomly discounted,
+	// in order to spread the retries of concurrent commits
+	Jitter float64 `json:"jitter,omitempty"`
+
+	// AttemptTimeout limits the duration of each attempt. Zero means no limit
+	AttemptTimeout time.Duration `json:"attempt_timeout,omitempty"`
+
+	// MaxThrottled is the qt of throttled calls performed again before giving up, which aren't
+	// counted as attempts. Zero means defaultMaxThrottled
+	MaxThrottled int `json:"max_throttled,omitempty"`
+
+	// Retryable classifies the errors which are worth a retry
+	// If it's nil, IsRetryable is used
+	Retryable func(error) bool `json:"-"`
+}
+
+// A RetryableError is an error which tells by itself if the failed action can be retried
+type RetryableError interface {
+	error
+	Retryable() bool
+}
+
+// IsRetryable is the default error classification of the retry policies
+// An error is retryable if it's a RetryableError or a temporary error (e.g. net.Error) which says so,
+// or if the attempt ran out of time
+func IsRetryable(err error) bool {
+	cause := errors.Cause(err)
+	if cause == context.DeadlineExceeded {
+		return true
+	}
+	if retryable, ok := cause.(RetryableError); ok {
+		return retryable.Retryable()
+	}
+	if temporary, ok := cause.(interface{ Temporary() bool }); ok {
+		return temporary.Temporary()
+	}
+	return false
+}
+
+// defaultMaxThrottled bounds the calls performed again once throttled, so a service which keeps
+// throttling them can't hang the delivery
+const defaultMaxThrottled = 10
+
+func (p *RetryPolicy) maxThrottled() int {
+	if p == nil || p.MaxThrottled < 1 {
+		return defaultMaxThrottled
+	}
+	return p.MaxThrottled
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p != nil && p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// backoff retrieves the wait before the given attempt (counting from 1)
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	if attempt < 2 || p.InitialBackoff <= 0 {
+		return 0
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-2))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		backoff -= backoff * math.Min(p.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(backoff)
+}
+
+// do performs the action following the policy, and retrieves the qt of attempts performed
+// Each attempt waits until the given rate limits allow it, and the ones throttled by the service
+// (see ThrottledError) are performed again once allowed, without counting as attempts, up to .MaxThrottled
+// It stops retrying once the action succeeds, its error isn't retryable, or the ctx is done
+func (p *RetryPolicy) do(
+	ctx context.Context,
+	limits []*RateLimit,
+	action func(context.Context) error,
+) (attempts int, err error) {
+	throttled, throttles := false, 0
+	for attempts < p.maxAttempts() || throttled {
+		if !throttled {
+			attempts++
+		}
+		if attempts > 1 && !throttled {
+			select {
+			case <-ctx.Done():
+				return attempts - 1, err
+			case <-time.After(p.backoff(attempts)):
+			}
+		}
+		// Notice the throttled calls aren't bounded by the .AttemptTimeout while waiting
+		if waitErr := waitRateLimits(ctx, limits); waitErr != nil {
+			if throttled { // The last call was performed, but throttled
+				return attempts, err
+			}
+			if err == nil {
+				err = waitErr
+			}
+			return attempts - 1, err
+		}
+
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if p != nil && p.AttemptTimeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, p.AttemptTimeout)
+		}
+		err = action(WithRateLimits(attemptCtx, limits...))
+		cancel()
+		if err == nil {
+			return attempts, nil
+		}
+		var retryAfter time.Duration
+		retryAfter, throttled = throttledBy(err)
+		if throttled {
+			throttles++
+			if throttles > p.maxThrottled() {
+				return attempts, err
+			}
+			if pauseErr := pauseRateLimits(ctx, limits, retryAfter); pauseErr != nil {
+				return attempts, err
+			}
+			continue
+		}
+		if ctx.Err() != nil || !p.retryable(err) {
+			return attempts, err
+		}
+	}
+	return attempts, err
+}
@@ -0,0 +1,207 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sebach1/rtc/schema"
+)
+
+// retryableErr is an error which tells by itself if it's retryable
+type retryableErr bool
+
+func (err retryableErr) Error() string   { return "retryable err" }
+func (err retryableErr) Retryable() bool { return bool(err) }
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "RETRYABLE error", err: retryableErr(true), want: true},
+		{name: "NOT RETRYABLE error", err: retryableErr(false), want: false},
+		{name: "attempt DEADLINE EXCEEDED", err: context.DeadlineExceeded, want: true},
+		{name: "CANCELED ctx", err: context.Canceled, want: false},
+		{name: "UNCLASSIFIED error", err: errFoo, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		policy  *RetryPolicy
+		attempt int
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "FIRST attempt doesn't wait",
+			policy:  &RetryPolicy{InitialBackoff: time.Second, Multiplier: 2},
+			attempt: 1,
+		},
+		{
+			name:    "EXPONENTIAL growth",
+			policy:  &RetryPolicy{InitialBackoff: time.Second, Multiplier: 2},
+			attempt: 4,
+			wantMin: 4 * time.Second,
+			wantMax: 4 * time.Second,
+		},
+		{
+			name:    "CAPPED by the max backoff",
+			policy:  &RetryPolicy{InitialBackoff: time.Second, Multiplier: 2, MaxBackoff: 3 * time.Second},
+			attempt: 4,
+			wantMin: 3 * time.Second,
+			wantMax: 3 * time.Second,
+		},
+		{
+			name:    "JITTER discounts up to its fraction",
+			policy:  &RetryPolicy{InitialBackoff: time.Second, Multiplier: 2, Jitter: 0.5},
+			attempt: 3,
+			wantMin: time.Second,
+			wantMax: 2 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.policy.backoff(tt.attempt)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("RetryPolicy.backoff() = %v, want between %v and %v", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_do(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		policy       *RetryPolicy
+		errs         []error // Returned by each attempt. Once exhausted, the action succeeds
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "NIL policy performs a SINGLE attempt",
+			errs:         []error{retryableErr(true)},
+			wantAttempts: 1,
+			wantErr:      retryableErr(true),
+		},
+		{
+			name:         "RETRIES until SUCCESS",
+			policy:       &RetryPolicy{MaxAttempts: 3},
+			errs:         []error{retryableErr(true), retryableErr(true)},
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up once ATTEMPTS are EXHAUSTED",
+			policy:       &RetryPolicy{MaxAttempts: 2},
+			errs:         []error{retryableErr(true), retryableErr(true), retryableErr(true)},
+			wantAttempts: 2,
+			wantErr:      retryableErr(true),
+		},
+		{
+			name:         "NOT RETRYABLE error",
+			policy:       &RetryPolicy{MaxAttempts: 3},
+			errs:         []error{errFoo},
+			wantAttempts: 1,
+			wantErr:      errFoo,
+		},
+		{
+			name:         "CUSTOM classification",
+			policy:       &RetryPolicy{MaxAttempts: 3, Retryable: func(err error) bool { return err == errFoo }},
+			errs:         []error{errFoo},
+			wantAttempts: 2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var calls int
+			gotAttempts, err := tt.policy.do(context.Background(), nil, func(ctx context.Context) error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			if err != tt.wantErr {
+				t.Errorf("RetryPolicy.do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotAttempts != tt.wantAttempts {
+				t.Errorf("RetryPolicy.do() attempts = %v, want %v", gotAttempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_do_attemptTimeout(t *testing.T) {
+	t.Parallel()
+	policy := &RetryPolicy{MaxAttempts: 2, AttemptTimeout: time.Millisecond}
+	gotAttempts, err := policy.do(context.Background(), nil, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != context.DeadlineExceeded {
+		t.Errorf("RetryPolicy.do() error = %v, wantErr %v", err, context.DeadlineExceeded)
+	}
+	if gotAttempts != 2 {
+		t.Errorf("RetryPolicy.do() attempts = %v, want %v", gotAttempts, 2)
+	}
+}
+
+func TestOwner_deliver(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		spy          *collabSpy
+		policy       *RetryPolicy
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "TRANSIENT failure is RETRIED",
+			spy:          &collabSpy{FailAt: 1, Err: retryableErr(true)},
+			policy:       &RetryPolicy{MaxAttempts: 3},
+			wantAttempts: 2,
+		},
+		{
+			name:         "PERMANENT failure is NOT retried",
+			spy:          &collabSpy{FailAt: 1, Err: errFoo},
+			policy:       &RetryPolicy{MaxAttempts: 3},
+			wantAttempts: 1,
+			wantErr:      errFoo,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+			comm := &Commit{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}, Reviewer: tt.spy,
+				Delivery: Delivery{RetryPolicy: tt.policy}}
+			res, err := own.deliver(context.Background(), comm, "create")
+			if err != tt.wantErr {
+				t.Errorf("Owner.deliver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := res.Attempts; got != tt.wantAttempts {
+				t.Errorf("Owner.deliver() attempts = %v, want %v", got, tt.wantAttempts)
+			}
+		})
+	}
+}
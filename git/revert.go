@@ -0,0 +1,301 @@
+package git
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/internal/store"
+)
+
+// Inverse returns the compensating commit of the merged one, which undoes its effects:
+// - A create is reverted by the deletion of the entity returned by the collaborator
+// - A delete is reverted by the creation of the entity described by the preImage
+// - An update is reverted by updating the entity with the column values of the preImage
+// Notice the preImage is the state of the entity before the commit was merged, and
+// it's only needed to revert deletions and updations
+func (comm *Commit) Inverse(preImage *Commit) (*Commit, error) {
+	if !comm.Is(Merged) {
+		return nil, errUnmergedRevert
+	}
+	commType, err := comm.Type()
+	if err != nil {
+		return nil, err
+	}
+	tableName, err := comm.TableName()
+	if err != nil {
+		return nil, err
+	}
+	opts, err := comm.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	var chgs []*Change
+	switch commType {
+	case "create":
+		entityId := comm.entityId()
+		if entityId.IsNil() {
+			return nil, errNilEntityId
+		}
+		chg, err := NewChange(entityId, tableName, "", nil, "delete", opts)
+		if err != nil {
+			return nil, err
+		}
+		chgs = append(chgs, chg)
+
+	case "delete":
+		if preImage == nil || len(preImage.Changes) == 0 {
+			return nil, errNilPreImage
+		}
+		for _, preChg := range preImage.Changes {
+			if preChg.ColumnName == "" || preChg.ValueType == "" {
+				continue
+			}
+			chg, err := NewChange("", tableName, preChg.ColumnName, preChg.Value(), "create", opts)
+			if err != nil {
+				return nil, err
+			}
+			chgs = append(chgs, chg)
+		}
+
+	case "update":
+		if preImage == nil {
+			return nil, errNilPreImage
+		}
+		for _, updChg := range comm.Changes {
+			preChg := preImage.changeByColumn(updChg.ColumnName)
+			if preChg == nil {
+				return nil, errIncompletePreImage
+			}
+			chg, err := NewChange(updChg.EntityId, tableName, updChg.ColumnName, preChg.Value(), "update", opts)
+			if err != nil {
+				return nil, err
+			}
+			chgs = append(chgs, chg)
+		}
+
+	default:
+		return nil, errIrreversibleType
+	}
+
+	if len(chgs) == 0 {
+		return nil, errIncompletePreImage
+	}
+	inv := NewCommit(chgs)
+	inv.BranchId = comm.BranchId
+	return inv, nil
+}
+
+// entityId retrieves the first not-nil EntityId of the changes
+// Notice the temporary ids are discarded, as they aren't known by the collaborators
+func (comm *Commit) entityId() integrity.Id {
+	for _, chg := range comm.Changes {
+		if !chg.EntityId.IsNil() && !chg.EntityId.IsTemporary() {
+			return chg.EntityId
+		}
+	}
+	return ""
+}
+
+// changeByColumn retrieves the change which describes the given column
+func (comm *Commit) changeByColumn(colName integrity.ColumnName) *Change {
+	for _, chg := range comm.Changes {
+		if chg.ColumnName == colName {
+			return chg
+		}
+	}
+	return nil
+}
+
+// capturePreImage retrieves, through the commit reviewer, the state of the entity before the commit
+// is merged and assigns it to .PreImage, in order to be able to revert it later
+func (own *Owner) capturePreImage(ctx context.Context, comm *Commit) error {
+	retrieval, err := own.preImageRetrieval(comm)
+	if err != nil {
+		return err
+	}
+	preImage, err := comm.Reviewer.Retrieve(ctx, retrieval)
+	if err != nil {
+		return err
+	}
+	if preImage == nil {
+		return errNilPreImage
+	}
+	ref := retrieval.Changes[0]
+	for _, chg := range preImage.Changes { // Normalizes the given state, which is usually decoded from its map version
+		chg.TableName = ref.TableName
+		chg.EntityId = ref.EntityId
+		chg.Options = ref.Options
+		chg.Type = ""
+	}
+	comm.PreImage = &Commit{Changes: preImage.Changes}
+	return nil
+}
+
+// preImageRetrieval builds the retrieve commit which asks for the columns the given commit will modify
+// Notice a deletion modifies all the columns of the entity, so they are taken from the project
+func (own *Owner) preImageRetrieval(comm *Commit) (*Commit, error) {
+	tableName, err := comm.TableName()
+	if err != nil {
+		return nil, err
+	}
+	opts, err := comm.Options()
+	if err != nil {
+		return nil, err
+	}
+	entityId := comm.entityId()
+
+	var colNames []integrity.ColumnName
+	commType, err := comm.Type()
+	if err != nil {
+		return nil, err
+	}
+	switch commType {
+	case "update":
+		colNames = comm.ColumnNames()
+	case "delete":
+		table, err := own.Project.TableByName(tableName)
+		if err != nil {
+			return nil, err
+		}
+		for _, col := range table.Columns {
+			colNames = append(colNames, col.Name)
+		}
+	}
+	if len(colNames) == 0 {
+		return nil, errIrreversibleType
+	}
+
+	retrieval := &Commit{}
+	for _, colName := range colNames {
+		retrieval.Changes = append(retrieval.Changes, &Change{
+			TableName:  tableName,
+			ColumnName: colName,
+			EntityId:   entityId,
+			Options:    opts,
+			Type:       "retrieve",
+		})
+	}
+	return retrieval, nil
+}
+
+// storePreImage persists the pre-image of the commit as a branchless commit and links it by .PreImageId
+func storePreImage(ctx context.Context, db *sqlx.DB, comm *Commit) error {
+	if comm.PreImage == nil || comm.PreImageId != 0 {
+		return nil
+	}
+	err := store.InsertIntoDB(ctx, db, comm.PreImage)
+	if err != nil {
+		return err
+	}
+	var chgs []store.Storable
+	for _, chg := range comm.PreImage.Changes {
+		chg.CommitId = comm.PreImage.Id
+		chgs = append(chgs, chg)
+	}
+	if len(chgs) > 0 {
+		err = store.InsertIntoDB(ctx, db, chgs...)
+		if err != nil {
+			return err
+		}
+	}
+	comm.PreImageId = comm.PreImage.Id
+	return nil
+}
+
+// fetchPreImage retrieves the pre-image of the commit by its .PreImageId and assigns it to .PreImage
+func (comm *Commit) fetchPreImage(ctx context.Context, db *sqlx.DB) error {
+	if comm.PreImageId == 0 {
+		return nil
+	}
+	preImage, err := CommitById(ctx, db, comm.PreImageId)
+	if err != nil {
+		return err
+	}
+	err = fetchCommitsChanges(ctx, db, []*Commit{preImage})
+	if err != nil {
+		return err
+	}
+	comm.PreImage = preImage
+	return nil
+}
+
+// storeCreatedEntityId persists the entity id returned by the collaborator onto the changes
+// of a merged create commit, so it can be reverted later
+func storeCreatedEntityId(ctx context.Context, db *sqlx.DB, comm *Commit) error {
+	if !comm.Is(Merged) || comm.Id == 0 {
+		return nil
+	}
+	commType, err := comm.Type()
+	if err != nil || commType != "create" {
+		return nil
+	}
+	entityId := comm.entityId()
+	if entityId.IsNil() {
+		return nil
+	}
+	_, err = db.ExecContext(ctx, `UPDATE changes SET entity_id=? WHERE commit_id=?`, entityId, comm.Id)
+	return err
+}
+
+// inverseOf builds the inverse of the merged commit of the branch with the given id. See Commit.Inverse
+// Notice a commit can't be reverted while another inverse of it is staged
+func (b *Branch) inverseOf(ctx context.Context, db *sqlx.DB, commitId int64) (*Commit, error) {
+	comm, err := CommitById(ctx, db, commitId)
+	if err != nil {
+		return nil, errors.Wrap(err, "find commit by id")
+	}
+	if comm.BranchId != b.Id {
+		return nil, errForeignCommit
+	}
+	var qtInvs int
+	err = db.GetContext(ctx, &qtInvs, `SELECT COUNT(*) FROM commits WHERE reverts_id=?`, comm.Id)
+	if err != nil {
+		return nil, errors.Wrap(err, "count commit inverses")
+	}
+	if qtInvs > 0 {
+		return nil, errRevertInFlight
+	}
+	err = fetchCommitsChanges(ctx, db, []*Commit{comm})
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch commit changes")
+	}
+	err = comm.fetchPreImage(ctx, db)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch commit pre image")
+	}
+	inv, err := comm.Inverse(comm.PreImage)
+	if err != nil {
+		return nil, errors.Wrap(err, "commit inverse")
+	}
+	inv.RevertsId = comm.Id
+	return inv, nil
+}
+
+// storeReverts transitions to Reverted the commits undone by the given ones which were merged
+func storeReverts(ctx context.Context, db *sqlx.DB, invs ...*Commit) error {
+	for _, inv := range invs {
+		if inv.RevertsId == 0 || !inv.Is(Merged) {
+			continue
+		}
+		comm, err := CommitById(ctx, db, inv.RevertsId)
+		if err != nil {
+			return errors.Wrap(err, "find reverted commit by id")
+		}
+		err = comm.transition(Reverted)
+		if err != nil {
+			return err
+		}
+		err = store.UpdateIntoDB(ctx, db, comm)
+		if err != nil {
+			return errors.Wrap(err, "update reverted commit into db")
+		}
+		err = storeTransitions(ctx, db, comm)
+		if err != nil {
+			return errors.Wrap(err, "store reverted commit transitions")
+		}
+	}
+	return nil
+}
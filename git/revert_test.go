@@ -0,0 +1,302 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/internal/test/assist"
+	"github.com/sebach1/rtc/schema"
+)
+
+func TestCommit_Inverse(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		preImage *Commit
+	}
+	merged := func(chgs ...*Change) *Commit {
+		return &Commit{State: Merged, Changes: chgs}
+	}
+	withEntity := func(chg *Change, id integrity.Id) *Change {
+		chg.EntityId = id
+		return chg
+	}
+	tests := []struct {
+		name      string
+		comm      *Commit
+		args      args
+		wantTypes []integrity.CRUD
+		wantVals  []interface{}
+		wantErr   error
+	}{
+		{
+			name:      "merged CREATE is reverted by a DELETE",
+			comm:      merged(withEntity(gChanges.Foo.Create.copy(t), "fooCreatedId")),
+			wantTypes: []integrity.CRUD{"delete"},
+			wantVals:  []interface{}{nil},
+		},
+		{
+			name:    "merged CREATE WITHOUT returned ENTITY",
+			comm:    merged(gChanges.Foo.Create.copy(t)),
+			wantErr: errNilEntityId,
+		},
+		{
+			name:      "merged UPDATE is reverted by an UPDATE with the pre-image values",
+			comm:      merged(gChanges.Foo.Update.copy(t)),
+			args:      args{preImage: &Commit{Changes: []*Change{gChanges.Foo.StringValue.copy(t)}}},
+			wantTypes: []integrity.CRUD{"update"},
+			wantVals:  []interface{}{gChanges.Foo.StringValue.StringValue},
+		},
+		{
+			name:    "merged UPDATE with INCOMPLETE pre-image",
+			comm:    merged(gChanges.Foo.Update.copy(t)),
+			args:    args{preImage: &Commit{Changes: []*Change{gChanges.Foo.ColumnName.copy(t)}}},
+			wantErr: errIncompletePreImage,
+		},
+		{
+			name:      "merged DELETE is reverted by a CREATE with the pre-image values",
+			comm:      merged(gChanges.Foo.Delete.copy(t)),
+			args:      args{preImage: &Commit{Changes: []*Change{gChanges.Foo.Update.copy(t)}}},
+			wantTypes: []integrity.CRUD{"create"},
+			wantVals:  []interface{}{gChanges.Foo.Update.StringValue},
+		},
+		{
+			name:    "merged DELETE WITHOUT pre-image",
+			comm:    merged(gChanges.Foo.Delete.copy(t)),
+			wantErr: errNilPreImage,
+		},
+		{
+			name:    "merged RETRIEVE",
+			comm:    merged(gChanges.Foo.Retrieve.copy(t)),
+			wantErr: errIrreversibleType,
+		},
+		{
+			name:    "UNMERGED commit",
+			comm:    &Commit{Changes: []*Change{gChanges.Foo.Update.copy(t)}},
+			wantErr: errUnmergedRevert,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.comm.Inverse(tt.args.preImage)
+			if err != tt.wantErr {
+				t.Errorf("Commit.Inverse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			var gotTypes []integrity.CRUD
+			var gotVals []interface{}
+			for _, chg := range got.Changes {
+				gotTypes = append(gotTypes, chg.Type)
+				gotVals = append(gotVals, chg.Value())
+			}
+			if diff := cmp.Diff(tt.wantTypes, gotTypes); diff != "" {
+				t.Errorf("Commit.Inverse() types mismatch (-want +got): %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantVals, gotVals); diff != "" {
+				t.Errorf("Commit.Inverse() values mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestOwner_capturePreImage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		comm       *Commit
+		collab     Collaborator
+		wantQtCols int
+		wantErr    bool
+	}{
+		{
+			name:       "UPDATE asks for its columns",
+			comm:       &Commit{Changes: []*Change{gChanges.Foo.Update.copy(t)}},
+			collab:     &collabMock{},
+			wantQtCols: 1,
+		},
+		{
+			name:       "DELETE asks for all the table columns",
+			comm:       &Commit{Changes: []*Change{gChanges.Foo.Delete.copy(t)}},
+			collab:     &collabMock{},
+			wantQtCols: len(gTables.Foo.Columns),
+		},
+		{
+			name:    "CREATE has no pre-image",
+			comm:    &Commit{Changes: []*Change{gChanges.Foo.Create.copy(t)}},
+			collab:  &collabMock{},
+			wantErr: true,
+		},
+		{
+			name:    "collaborator RETRIEVE ERRORED",
+			comm:    &Commit{Changes: []*Change{gChanges.Foo.Update.copy(t)}},
+			collab:  &collabMock{Err: errFoo},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+			tt.comm.Reviewer = tt.collab
+			err := own.capturePreImage(context.Background(), tt.comm)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Owner.capturePreImage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got := len(tt.comm.PreImage.Changes); got != tt.wantQtCols {
+				t.Errorf("Owner.capturePreImage() qt of columns = %v, want %v", got, tt.wantQtCols)
+			}
+		})
+	}
+}
+
+func TestOwner_deliver_capturePreImage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		spy       *collabSpy
+		wantCalls []integrity.CRUD
+		wantErr   error
+	}{
+		{
+			name:      "UPDATE after its pre-image",
+			spy:       &collabSpy{},
+			wantCalls: []integrity.CRUD{"retrieve", "update"},
+		},
+		{
+			name:      "pre-image RETRIEVE ERRORED fails the commit BEFORE its delivery",
+			spy:       &collabSpy{FailAt: 1, Err: errFoo},
+			wantCalls: []integrity.CRUD{"retrieve"},
+			wantErr:   errFoo,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+			own.CapturePreImages = true
+			comm := &Commit{Id: 1, Changes: []*Change{gChanges.Foo.Update.copy(t)}, Reviewer: tt.spy}
+			_, err := own.deliver(context.Background(), comm, "update")
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("Owner.deliver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantCalls, tt.spy.Calls); diff != "" {
+				t.Errorf("Owner.deliver() calls mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestRevert(t *testing.T) {
+	branch := gBranches.Foo.copy(t)
+	branchCols := []string{"id", "name", "index_id"}
+	commCols := []string{"id", "branch_id", "state"}
+	chgCols := []string{"id", "table_name", "entity_id", "type", "commit_id"}
+	stubInverse := func(mock sqlmock.Sqlmock, id int64) {
+		(&assist.QueryStubber{Expect: "SELECT * FROM commits WHERE id=?", Rows: sqlmock.NewRows(commCols).AddRow(id, branch.Id, Merged)}).Stub(mock)
+		(&assist.QueryStubber{Expect: "SELECT COUNT(*) FROM commits WHERE reverts_id=?", Rows: sqlmock.NewRows([]string{"count"}).AddRow(0)}).Stub(mock)
+		(&assist.QueryStubber{
+			Expect: "SELECT * FROM changes WHERE commit_id IN (?)",
+			Rows:   sqlmock.NewRows(chgCols).AddRow(id*10, "fooTable", "fooCreatedId", "create", id),
+		}).Stub(mock)
+	}
+	tests := []struct {
+		name      string
+		commitIds []int64
+		stub      func(sqlmock.Sqlmock)
+		wantRevs  []int64
+		wantErr   error
+	}{
+		{
+			name:      "stages the inverses from the NEWEST to the OLDEST commit",
+			commitIds: []int64{1, 2},
+			stub: func(mock sqlmock.Sqlmock) {
+				stubInverse(mock, 2)
+				stubInverse(mock, 1)
+				mock.ExpectBegin()
+				for id := int64(1); id <= 2; id++ {
+					(&assist.QueryStubber{Expect: "INSERT INTO commits", Rows: sqlmock.NewRows([]string{"id"}).AddRow(id + 2)}).Stub(mock)
+					(&assist.QueryStubber{Expect: "INSERT INTO changes", Rows: sqlmock.NewRows([]string{"id"}).AddRow(id + 20)}).Stub(mock)
+				}
+				mock.ExpectCommit()
+			},
+			wantRevs: []int64{2, 1},
+		},
+		{
+			name:      "a FOREIGN commit stages NONE",
+			commitIds: []int64{2, 1},
+			stub: func(mock sqlmock.Sqlmock) {
+				stubInverse(mock, 2)
+				(&assist.QueryStubber{Expect: "SELECT * FROM commits WHERE id=?", Rows: sqlmock.NewRows(commCols).AddRow(1, branch.Id+1, Merged)}).Stub(mock)
+			},
+			wantErr: errForeignCommit,
+		},
+		{
+			name:      "an ALREADY REVERTING commit stages NONE",
+			commitIds: []int64{1},
+			stub: func(mock sqlmock.Sqlmock) {
+				(&assist.QueryStubber{Expect: "SELECT * FROM commits WHERE id=?", Rows: sqlmock.NewRows(commCols).AddRow(1, branch.Id, Merged)}).Stub(mock)
+				(&assist.QueryStubber{Expect: "SELECT COUNT(*) FROM commits WHERE reverts_id=?", Rows: sqlmock.NewRows([]string{"count"}).AddRow(1)}).Stub(mock)
+			},
+			wantErr: errRevertInFlight,
+		},
+		{
+			name:      "staging FAILS rolls back the staged inverses",
+			commitIds: []int64{1, 2},
+			stub: func(mock sqlmock.Sqlmock) {
+				stubInverse(mock, 2)
+				stubInverse(mock, 1)
+				mock.ExpectBegin()
+				(&assist.QueryStubber{Expect: "INSERT INTO commits", Rows: sqlmock.NewRows([]string{"id"}).AddRow(3)}).Stub(mock)
+				(&assist.QueryStubber{Expect: "INSERT INTO changes", Rows: sqlmock.NewRows([]string{"id"}).AddRow(21)}).Stub(mock)
+				(&assist.QueryStubber{Expect: "INSERT INTO commits", Err: errFoo}).Stub(mock)
+				mock.ExpectRollback()
+			},
+			wantErr: errFoo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := mockDBWithOptions(t)
+			(&assist.QueryStubber{
+				Expect: "SELECT * FROM branches WHERE name=?",
+				Rows:   sqlmock.NewRows(branchCols).AddRow(branch.Id, branch.Name, branch.IndexId),
+			}).Stub(mock)
+			tt.stub(mock)
+			commitIds := append([]int64(nil), tt.commitIds...)
+			got, err := Revert(context.Background(), db, integrity.BranchName(branch.Name), commitIds...)
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("Revert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Revert() unfulfilled expectations: %v", err)
+			}
+			if diff := cmp.Diff(tt.commitIds, commitIds); diff != "" {
+				t.Errorf("Revert() modified the given commit ids (-want +got): %s", diff)
+			}
+			var gotRevs []int64
+			for _, inv := range got {
+				gotRevs = append(gotRevs, inv.RevertsId)
+				if inv.Is(Reverted) {
+					t.Errorf("Revert() marked the inverse %v as REVERTED", inv.Id)
+				}
+			}
+			if diff := cmp.Diff(tt.wantRevs, gotRevs); diff != "" {
+				t.Errorf("Revert() reverted commits mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
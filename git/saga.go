@@ -0,0 +1,124 @@
+package git
+
+import (
+	"context"
+)
+
+// A Compensation is the action which undoes the effects of a merged commit
+type Compensation func(context.Context, *Commit) error
+
+// mergeSaga merges the commits of the pull request one by one, following its dependency order
+// Once any of them fails, the already merged ones are compensated in reverse order
+// Notice that every compensation step is recorded as a Result in the .Summary
+func (own *Owner) mergeSaga(ctx context.Context, pR *PullRequest) {
+	for _, comm := range pR.Commits {
+		if comm.Is(Rejected) { // Any rejected commit aborts the saga before touching the collaborators
+			own.abortSaga(pR.Commits, errSagaAborted)
+			return
+		}
+	}
+
+	graph, err := pR.dependencies()
+	if err != nil {
+		own.abortSaga(pR.Commits, errSagaAborted)
+		return
+	}
+	sorted, err := pR.order(graph)
+	if err != nil {
+		own.abortSaga(pR.Commits, errSagaAborted)
+		return
+	}
+
+	placeholders := pR.placeholders()
+	var merged []*Commit
+	for i, comm := range sorted {
+		if err := ctx.Err(); err != nil { // Canceled: the pending commits aren't started
+			own.abortSaga(sorted[i:], err)
+			own.compensate(detach(ctx), merged)
+			return
+		}
+		comm.resolveRefs(graph[comm], placeholders)
+		res, err := resultOf(comm, nil), comm.transition(InFlight)
+		if err == nil {
+			res, err = own.sagaStep(ctx, comm)
+		}
+		comm.settle(err)
+		res.Error = err
+		own.report(res)
+		if err != nil {
+			own.compensate(detach(ctx), merged) // Even if the orchestration was canceled
+			return
+		}
+		merged = append(merged, comm)
+	}
+}
+
+// sagaStep delivers the commit ensuring it can be compensated afterwards
+func (own *Owner) sagaStep(ctx context.Context, comm *Commit) (*Result, error) {
+	commType, err := comm.Type()
+	if err != nil {
+		return resultOf(comm, err), err
+	}
+	if comm.Compensate == nil && (commType == "update" || commType == "delete") && comm.PreImage == nil {
+		err = comm.Reviewer.Init(ctx)
+		if err != nil {
+			return resultOf(comm, err), err
+		}
+		err = own.capturePreImage(ctx, comm)
+		if err != nil {
+			return resultOf(comm, err), err
+		}
+	}
+	return own.deliver(ctx, comm, commType)
+}
+
+// compensate undoes the given merged commits in reverse order
+func (own *Owner) compensate(ctx context.Context, merged []*Commit) {
+	for i := len(merged) - 1; i >= 0; i-- {
+		comm := merged[i]
+		res, err := own.compensation(ctx, comm)
+		if err == nil {
+			_ = comm.transition(Reverted)
+		}
+		res.Error, res.Compensation = err, true
+		own.report(res)
+	}
+}
+
+// compensation executes the compensating action of the commit
+// Unless the commit registers its own Compensation, the inverse commit is
+// delivered through the same reviewer. See Commit.Inverse
+func (own *Owner) compensation(ctx context.Context, comm *Commit) (*Result, error) {
+	if comm.Compensate != nil {
+		res := newResult(comm)
+		err := comm.Compensate(ctx, comm)
+		return res.finish(comm, nil, err), err
+	}
+	inv, err := comm.Inverse(comm.PreImage)
+	if err == errIrreversibleType { // e.g: retrievals doesn't need to be compensated
+		return resultOf(comm, nil), nil
+	}
+	if err != nil {
+		return resultOf(comm, err), err
+	}
+	invType, err := inv.Type()
+	if err != nil {
+		return resultOf(comm, err), err
+	}
+	inv.Reviewer = comm.Reviewer
+	inv.Delivery = comm.Delivery
+	res, err := own.deliver(ctx, inv, invType)
+	res.CommitId = comm.Id
+	return res, err
+}
+
+// abortSaga fails every not-rejected commit of the given ones, recording its abortion by the err
+func (own *Owner) abortSaga(comms []*Commit, err error) {
+	for _, comm := range comms {
+		if comm.Is(Rejected) {
+			continue
+		}
+		_ = comm.transition(Failed)
+		own.report(&Result{CommitId: comm.Id, Error: err})
+	}
+}
@@ -0,0 +1,108 @@
+package git
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/schema"
+)
+
+func TestOwner_mergeSaga(t *testing.T) {
+	t.Parallel()
+	withEntity := func(chg *Change, id integrity.Id) *Change {
+		chg.EntityId = id
+		return chg
+	}
+	tests := []struct {
+		name              string
+		spy               *collabSpy
+		commits           []*Commit
+		wantCalls         []integrity.CRUD
+		wantStates        []CommitState
+		wantQtResErrs     int
+		wantQtCompensated int
+	}{
+		{
+			name: "fully successful",
+			spy:  &collabSpy{},
+			commits: []*Commit{
+				{Changes: []*Change{gChanges.Foo.Create.copy(t)}, State: Delegated},
+				{Changes: []*Change{gChanges.Foo.Update.copy(t)}, State: Delegated},
+			},
+			wantCalls:  []integrity.CRUD{"create", "retrieve", "update"},
+			wantStates: []CommitState{Merged, Merged},
+		},
+		{
+			name: "last commit fails and the others are compensated in reverse order",
+			spy:  &collabSpy{FailAt: 5, Err: errFoo},
+			commits: []*Commit{
+				{Changes: []*Change{withEntity(gChanges.Foo.Create.copy(t), "fooCreatedId")}, State: Delegated},
+				{Changes: []*Change{gChanges.Foo.Update.copy(t)}, State: Delegated},
+				{Changes: []*Change{gChanges.Foo.Delete.copy(t)}, State: Delegated},
+			},
+			wantCalls: []integrity.CRUD{
+				"create", "retrieve", "update", "retrieve", "delete", // saga
+				"update", "delete", // compensations
+			},
+			wantStates:        []CommitState{Reverted, Reverted, Failed},
+			wantQtResErrs:     1,
+			wantQtCompensated: 2,
+		},
+		{
+			name: "a REJECTED commit ABORTS the saga",
+			spy:  &collabSpy{},
+			commits: []*Commit{
+				{Changes: []*Change{gChanges.Foo.Create.copy(t)}, State: Delegated},
+				{Changes: []*Change{gChanges.Foo.Update.copy(t)}, State: Rejected},
+			},
+			wantStates:    []CommitState{Failed, Rejected},
+			wantQtResErrs: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+			own.Saga = true
+			own.Summary = make(chan *Result, 2*len(tt.commits))
+			own.Waiter = &sync.WaitGroup{}
+			for _, comm := range tt.commits {
+				comm.Reviewer = tt.spy
+			}
+			own.Waiter.Add(1)
+			own.Merge(context.Background(), &PullRequest{Commits: tt.commits})
+			close(own.Summary)
+
+			if diff := cmp.Diff(tt.wantCalls, tt.spy.Calls); diff != "" {
+				t.Errorf("Owner.Merge() saga calls mismatch (-want +got): %s", diff)
+			}
+			var gotStates []CommitState
+			for _, comm := range tt.commits {
+				gotStates = append(gotStates, comm.State)
+			}
+			if diff := cmp.Diff(tt.wantStates, gotStates); diff != "" {
+				t.Errorf("Owner.Merge() saga states mismatch (-want +got): %s", diff)
+			}
+			var gotQtResErrs, gotQtCompensated int
+			for res := range own.Summary {
+				if res.Compensation {
+					gotQtCompensated++
+					continue
+				}
+				if res.Error != nil {
+					gotQtResErrs++
+				}
+			}
+			if gotQtResErrs != tt.wantQtResErrs {
+				t.Errorf("Owner.Merge() saga gotQtResErrs = %v, want %v", gotQtResErrs, tt.wantQtResErrs)
+			}
+			if gotQtCompensated != tt.wantQtCompensated {
+				t.Errorf("Owner.Merge() saga gotQtCompensated = %v, want %v", gotQtCompensated, tt.wantQtCompensated)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package git
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/integrity"
+)
+
+// A SelectionStrategy is the way a team chooses among the members assigned to the same table
+type SelectionStrategy string
+
+const (
+	// StrategyFailover chooses the members by its .Priority, which is the default
+	StrategyFailover SelectionStrategy = "failover"
+	// StrategyRoundRobin chooses the members in turns, by the id of the commits
+	StrategyRoundRobin SelectionStrategy = "round-robin"
+	// StrategyWeighted chooses the members randomly, in proportion to its .Weight
+	StrategyWeighted SelectionStrategy = "weighted"
+)
+
+// Validate checks the strategy is a known one
+// Notice the zero-valued strategy is the StrategyFailover
+func (s SelectionStrategy) Validate() error {
+	switch s {
+	case "", StrategyFailover, StrategyRoundRobin, StrategyWeighted:
+		return nil
+	}
+	return errUnknownStrategy
+}
+
+// A Delegation is the assignment of the commits to a member, with the settings its deliveries follow
+type Delegation struct {
+	Collab         Collaborator
+	RetryPolicy    *RetryPolicy
+	Timeout        time.Duration
+	MaxConcurrency int
+	RateLimits     []*RateLimit
+	Breaker        *CircuitBreaker
+
+	// fallbackOf is the breaker of the member the delegation is the fallback of, which is only
+	// failed over to while its circuit is open. See Member.Fallback
+	fallbackOf *CircuitBreaker
+}
+
+// AddReplica adds another member to the table, besides the ones already assigned to it
+// The members of the same table are chosen following the .Strategy of the team
+func (t *Team) AddReplica(tableName integrity.TableName, collab Collaborator) *Member {
+	member := &Member{AssignedTable: tableName, Collab: collab}
+	t.Members = append(t.Members, member)
+	return member
+}
+
+// delegation retrieves the delegation to the given member, which takes the settings of the team
+// the member doesn't override
+func (t *Team) delegation(member *Member, collab Collaborator) *Delegation {
+	d := &Delegation{
+		Collab:         collab,
+		RetryPolicy:    t.RetryPolicy,
+		Timeout:        t.Timeout,
+		MaxConcurrency: member.MaxConcurrency,
+		Breaker:        member.Breaker,
+	}
+	if member.RetryPolicy != nil {
+		d.RetryPolicy = member.RetryPolicy
+	}
+	if member.Timeout > 0 {
+		d.Timeout = member.Timeout
+	}
+	if t.RateLimit != nil {
+		d.RateLimits = append(d.RateLimits, t.RateLimit)
+	}
+	if member.RateLimit != nil {
+		d.RateLimits = append(d.RateLimits, member.RateLimit)
+	}
+	return d
+}
+
+// delegations retrieves the delegations of the commits over the given tableName, sorted by the .Strategy
+// The first one is the chosen, and the rest are the ones the deliveries fail over to. See Commit.Failover
+// The fallbacks of the members are at the end, and members whose circuit breaker is already open are only
+// replaced by them. See Member.Breaker
+// The turn is the one of the delegated commit (i.e. its id), which rotates the members by the StrategyRoundRobin
+func (t *Team) delegations(tableName integrity.TableName, turn int64) ([]*Delegation, error) {
+	err := t.Strategy.Validate()
+	if err != nil {
+		return nil, err
+	}
+	var members []*Member
+	assigned := false
+	for _, member := range t.Members {
+		if member.AssignedTable != tableName {
+			continue
+		}
+		assigned = true
+		if member.Collab != nil {
+			members = append(members, member)
+		}
+	}
+	if !assigned {
+		return nil, errNoMembers
+	}
+	if len(members) == 0 {
+		return nil, errNoCollaborators
+	}
+
+	var delegations, fallbacks []*Delegation
+	for _, member := range t.sort(members, turn) {
+		if member.Breaker.State() != BreakerOpen {
+			delegations = append(delegations, t.delegation(member, member.Collab))
+		}
+		if member.Fallback != nil && member.Breaker != nil {
+			fallback := t.delegation(member, member.Fallback)
+			fallback.Breaker = nil // The breaker is of the member
+			fallback.fallbackOf = member.Breaker
+			fallbacks = append(fallbacks, fallback)
+		}
+	}
+	delegations = append(delegations, fallbacks...)
+	if len(delegations) == 0 {
+		return nil, errCircuitOpen
+	}
+	return delegations, nil
+}
+
+// sort retrieves the given members in the order they're chosen by the .Strategy at the given turn
+func (t *Team) sort(members []*Member, turn int64) []*Member {
+	sorted := make([]*Member, len(members))
+	copy(sorted, members)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Priority < sorted[j].Priority })
+	if len(sorted) < 2 {
+		return sorted
+	}
+
+	first := 0
+	switch t.Strategy {
+	case StrategyRoundRobin: // Rotates the members, so each of them is the first in turns
+		first = int(turn % int64(len(sorted)))
+		if first < 0 {
+			first += len(sorted)
+		}
+		return append(sorted[first:], sorted[:first]...)
+	case StrategyWeighted: // Picks the first one, and the rest are failed over by priority
+		total := 0
+		for _, member := range sorted {
+			total += member.weight()
+		}
+		pick := rand.Intn(total)
+		for i, member := range sorted {
+			if pick < member.weight() {
+				first = i
+				break
+			}
+			pick -= member.weight()
+		}
+	}
+	return append([]*Member{sorted[first]}, append(sorted[:first:first], sorted[first+1:]...)...)
+}
+
+// delegateTo assigns the commit to the given delegation
+func (comm *Commit) delegateTo(d *Delegation) {
+	comm.Reviewer = d.Collab
+	comm.RetryPolicy = d.RetryPolicy
+	comm.Timeout = d.Timeout
+	comm.MaxConcurrency = d.MaxConcurrency
+	comm.RateLimits = d.RateLimits
+	comm.Breaker = d.Breaker
+}
+
+// failOver delegates the commit to the next of its .Failover, in case its delivery failed with
+// the given err as its reviewer is unavailable (i.e. the err is retryable or its circuit is open)
+// The fallbacks of the members whose circuit isn't open by then are skipped
+// It tells if the commit was delegated again
+func (comm *Commit) failOver(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	if !IsRetryable(err) && errors.Cause(err) != errCircuitOpen {
+		return false
+	}
+	for len(comm.Failover) > 0 {
+		d := comm.Failover[0]
+		comm.Failover = comm.Failover[1:]
+		if d.fallbackOf != nil && d.fallbackOf.State() != BreakerOpen {
+			continue
+		}
+		comm.delegateTo(d)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/schema"
+)
+
+func TestTeam_delegations(t *testing.T) {
+	t.Parallel()
+	foo, bar, baz := &collabMock{}, &collabMock{}, &collabMock{}
+	fallback := &collabMock{}
+	open := &CircuitBreaker{FailureThreshold: 1, OpenTimeout: time.Hour}
+	open.record(retryableErr(true))
+	names := map[Collaborator]string{foo: "foo", bar: "bar", baz: "baz", fallback: "fallback"}
+
+	tests := []struct {
+		name     string
+		strategy SelectionStrategy
+		members  []*Member
+		turn     int64
+		want     []string
+		wantErr  error
+	}{
+		{
+			name: "FAILOVER sorts by PRIORITY",
+			members: []*Member{
+				{Collab: foo, Priority: 2}, {Collab: bar, Priority: 1}, {Collab: baz, Priority: 2},
+			},
+			want: []string{"bar", "foo", "baz"},
+		},
+		{
+			name:     "ROUND ROBIN rotates by the TURN",
+			strategy: StrategyRoundRobin,
+			members:  []*Member{{Collab: foo}, {Collab: bar}, {Collab: baz}},
+			turn:     4,
+			want:     []string{"bar", "baz", "foo"},
+		},
+		{
+			name: "OPEN circuit is REPLACED by its FALLBACK at the end",
+			members: []*Member{
+				{Collab: foo, Breaker: open, Fallback: fallback}, {Collab: bar, Priority: 1},
+			},
+			want: []string{"bar", "fallback"},
+		},
+		{
+			name: "CLOSED circuit is FOLLOWED by its FALLBACK at the end",
+			members: []*Member{
+				{Collab: foo, Breaker: &CircuitBreaker{}, Fallback: fallback}, {Collab: bar, Priority: 1},
+			},
+			want: []string{"foo", "bar", "fallback"},
+		},
+		{
+			name:    "FALLBACK WITHOUT breaker is never used",
+			members: []*Member{{Collab: foo, Fallback: fallback}},
+			want:    []string{"foo"},
+		},
+		{
+			name:    "OPEN circuit WITHOUT fallback",
+			members: []*Member{{Collab: foo, Breaker: open}},
+			wantErr: errCircuitOpen,
+		},
+		{
+			name:    "members WITHOUT collaborators",
+			members: []*Member{{}},
+			wantErr: errNoCollaborators,
+		},
+		{
+			name:     "UNKNOWN strategy",
+			strategy: "foo",
+			members:  []*Member{{Collab: foo}},
+			wantErr:  errUnknownStrategy,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			team := &Team{Strategy: tt.strategy}
+			for _, member := range tt.members {
+				member.AssignedTable = gTables.Foo.Name
+				team.Members = append(team.Members, member)
+			}
+			got, err := team.delegations(gTables.Foo.Name, tt.turn)
+			if err != tt.wantErr {
+				t.Fatalf("Team.delegations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var gotNames []string
+			for _, d := range got {
+				gotNames = append(gotNames, names[d.Collab])
+			}
+			if diff := cmp.Diff(tt.want, gotNames); diff != "" {
+				t.Errorf("Team.delegations() mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestTeam_delegations_weighted(t *testing.T) {
+	t.Parallel()
+	light, heavy := &collabMock{}, &collabMock{}
+	team := &Team{Strategy: StrategyWeighted}
+	team.AddReplica(gTables.Foo.Name, light)
+	team.AddReplica(gTables.Foo.Name, heavy).Weight = 3
+
+	var qtHeavy int
+	for i := 0; i < 1000; i++ {
+		got, err := team.delegations(gTables.Foo.Name, 0)
+		if err != nil {
+			t.Fatalf("Team.delegations() error = %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("Team.delegations() qt = %v, want %v", len(got), 2)
+		}
+		if got[0].Collab == heavy {
+			qtHeavy++
+		}
+	}
+	if qtHeavy < 650 || qtHeavy > 850 { // 750 expected
+		t.Errorf("Team.delegations() chose the heavier member %v times out of 1000", qtHeavy)
+	}
+}
+
+func TestOwner_Orchestrate_failover(t *testing.T) {
+	t.Parallel()
+	replica := &collabSpy{}
+	team := gTeams.Foo.copy(t).mock(gTables.Foo.Name, retryableErr(true))
+	team.AddReplica(gTables.Foo.Name, replica).Priority = 1
+
+	pR := &PullRequest{Commits: []*Commit{{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}}}}
+	own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+	own.Waiter.Add(1)
+	go own.Orchestrate(context.Background(), &Community{team}, gSchemas.Foo.Name, pR)
+	err := own.WaitAndClose()
+	if err != nil {
+		t.Fatalf("Owner.Orchestrate() error = %v", err)
+	}
+
+	if !pR.Commits[0].Is(Merged) {
+		t.Errorf("Owner.Orchestrate() commit is %v, want %v", pR.Commits[0].State, Merged)
+	}
+	if len(replica.Calls) != 1 {
+		t.Errorf("Owner.Orchestrate() did NOT FAIL OVER to the replica")
+	}
+	res := <-own.Summary
+	if res.Error != nil || res.Collaborator != "*git.collabSpy" || res.Attempts != 2 {
+		t.Errorf("Owner.Orchestrate() result = %+v, want the one of the replica after 2 attempts", res)
+	}
+}
+
+func TestOwner_Orchestrate_fallback(t *testing.T) {
+	t.Parallel()
+	fallback := &collabSpy{}
+	team := gTeams.Foo.copy(t).mock(gTables.Foo.Name, retryableErr(true))
+	team.Members[0].Breaker = &CircuitBreaker{FailureThreshold: 1, OpenTimeout: time.Hour}
+	team.Members[0].Fallback = fallback
+
+	pR := &PullRequest{Commits: []*Commit{{Id: 1, Changes: []*Change{gChanges.Foo.Create.copy(t)}}}}
+	own := newOwnerUnsafe(&schema.Planisphere{gSchemas.Foo})
+	own.Waiter.Add(1)
+	go own.Orchestrate(context.Background(), &Community{team}, gSchemas.Foo.Name, pR)
+	err := own.WaitAndClose()
+	if err != nil {
+		t.Fatalf("Owner.Orchestrate() error = %v", err)
+	}
+
+	if !pR.Commits[0].Is(Merged) {
+		t.Errorf("Owner.Orchestrate() commit is %v, want %v", pR.Commits[0].State, Merged)
+	}
+	if len(fallback.Calls) != 1 {
+		t.Errorf("Owner.Orchestrate() did NOT FAIL OVER to the fallback once the circuit opened")
+	}
+}
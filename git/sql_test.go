@@ -1,5 +1,14 @@
 package git
 
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/sebach1/rtc/internal/name"
+)
+
 func isExcluded(exclusions []string, name string) bool {
 	for _, exc := range exclusions {
 		if exc == name {
@@ -8,3 +17,25 @@ func isExcluded(exclusions []string, name string) bool {
 	}
 	return false
 }
+
+// optionsConverter lets the mocked db take the changes, as its Options aren't driver values
+type optionsConverter struct{}
+
+func (optionsConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	if _, ok := v.(Options); ok {
+		return nil, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+// mockDBWithOptions is the mocked db which is able to store changes. See thelper.MockDB
+func mockDBWithOptions(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New(sqlmock.ValueConverterOption(optionsConverter{}))
+	if err != nil {
+		t.Fatalf("could connect to database: %v", err)
+	}
+	db := sqlx.NewDb(mockDB, "sqlmock")
+	db.MapperFunc(name.ToSnakeCase)
+	return db, mock
+}
@@ -0,0 +1,283 @@
+package git
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/internal/store"
+	"github.com/sebach1/rtc/schema"
+)
+
+// A Stash is a named entry which parks the uncommitted changes of the index of a branch
+// Notice the changes are kept on an index of its own, so they aren't committed along with the branch ones
+type Stash struct {
+	Id       int64  `json:"id,omitempty"`
+	BranchId int64  `json:"branch_id,omitempty"`
+	IndexId  int64  `json:"index_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+
+	Changes []*Change `json:"changes,omitempty"`
+}
+
+// uncommittedChanges retrieves the changes of the given index which weren't committed yet
+func uncommittedChanges(ctx context.Context, db *sqlx.DB, indexId int64) ([]*Change, error) {
+	var chgs []*Change
+	err := db.SelectContext(ctx, &chgs, `SELECT * FROM changes WHERE commit_id=0 AND index_id=? ORDER BY id`, indexId)
+	if err != nil {
+		return nil, err
+	}
+	return chgs, nil
+}
+
+// stashByName finds the stash of the branch given its name
+// In case no name is given, it retrieves the latest stash
+func (b *Branch) stashByName(ctx context.Context, db *sqlx.DB, name string) (*Stash, error) {
+	st := &Stash{}
+	var err error
+	if name == "" {
+		err = db.GetContext(ctx, st, `SELECT * FROM stashes WHERE branch_id=? ORDER BY id DESC LIMIT 1`, b.Id)
+	} else {
+		err = db.GetContext(ctx, st, `SELECT * FROM stashes WHERE branch_id=? AND name=?`, b.Id, name)
+	}
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, errStashNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// unstash adds the stashed changes onto the index, as Index.add does, retrieving the ones which
+// were already on it and the conflicts with the changes of the index they overrode
+func (idx *Index) unstash(stashed []*Change) (dups []*Change, overrides []*Conflict, err error) {
+	for _, chg := range stashed {
+		if idx.containsChange(chg) {
+			dups = append(dups, chg)
+			continue
+		}
+		for _, otherChg := range idx.Changes {
+			if Overrides(chg, otherChg) {
+				overrides = append(overrides, &Conflict{Source: chg, Target: otherChg})
+			}
+		}
+		err = idx.add(chg)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return
+}
+
+// StashPush parks the uncommitted changes of the index of the branch onto a new stash with the given name
+func StashPush(
+	ctx context.Context,
+	db *sqlx.DB,
+	branchName integrity.BranchName,
+	name string,
+) (*Stash, error) {
+	if name == "" {
+		return nil, errNilStashName
+	}
+	branch, err := BranchByName(ctx, db, branchName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find branch by name")
+	}
+	_, err = branch.stashByName(ctx, db, name)
+	if err == nil {
+		return nil, errStashInUse
+	}
+	if err != errStashNotFound {
+		return nil, errors.Wrap(err, "find stash by name")
+	}
+	chgs, err := uncommittedChanges(ctx, db, branch.IndexId)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch uncommitted changes")
+	}
+	if len(chgs) == 0 {
+		return nil, errNothingToStash
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer tx.Rollback() // No-op once committed
+	idxId, err := insertIndex(ctx, tx)
+	if err != nil {
+		return nil, errors.Wrap(err, "insert stash index")
+	}
+	st := &Stash{BranchId: branch.Id, IndexId: idxId, Name: name}
+	err = store.InsertIntoDB(ctx, tx, st)
+	if err != nil {
+		return nil, errors.Wrap(err, "insert stash into db")
+	}
+	var batch []store.Storable
+	for _, chg := range chgs {
+		chg.IndexId = idxId
+		batch = append(batch, chg)
+	}
+	err = store.UpdateIntoDB(ctx, tx, batch...)
+	if err != nil {
+		return nil, errors.Wrap(err, "update stashed changes into db")
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "commit tx")
+	}
+	st.Changes = chgs
+	return st, nil
+}
+
+// StashPop re-applies the changes of the stash with the given name (or the latest one) onto the
+// index of the branch, and drops the stash
+// It retrieves the conflicts with the uncommitted changes of the index which were overridden, and
+// thus removed. See Index.add
+// Notice the stashed changes are validated against the branch as any added change. See Add
+func StashPop(
+	ctx context.Context,
+	db *sqlx.DB,
+	project *schema.Planisphere,
+	branchName integrity.BranchName,
+	name string,
+) (*Stash, []*Conflict, error) {
+	branch, err := BranchByName(ctx, db, branchName)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "find branch by name")
+	}
+	st, err := branch.stashByName(ctx, db, name)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "find stash by name")
+	}
+	st.Changes, err = uncommittedChanges(ctx, db, st.IndexId)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "fetch stashed changes")
+	}
+	for _, chg := range st.Changes {
+		err = branch.validate(project, "", chg)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "validate stashed change")
+		}
+	}
+	chgs, err := uncommittedChanges(ctx, db, branch.IndexId)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "fetch uncommitted changes")
+	}
+
+	idx := &Index{Id: branch.IndexId, Changes: chgs}
+	dups, overrides, err := idx.unstash(st.Changes)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "index unstash changes")
+	}
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "begin tx")
+	}
+	defer tx.Rollback() // No-op once committed
+	isDup := make(map[*Change]bool, len(dups))
+	for _, chg := range dups {
+		isDup[chg] = true
+		err = store.DeleteFromDB(ctx, tx, chg)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "delete duplicated change from db")
+		}
+	}
+	for _, cflct := range overrides {
+		err = store.DeleteFromDB(ctx, tx, cflct.Target)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "delete overridden change from db")
+		}
+	}
+	var batch []store.Storable
+	for _, chg := range st.Changes {
+		if isDup[chg] {
+			continue
+		}
+		chg.IndexId = branch.IndexId
+		batch = append(batch, chg)
+	}
+	err = store.UpdateIntoDB(ctx, tx, batch...)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "update unstashed changes into db")
+	}
+	err = dropStash(ctx, tx, st)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "commit tx")
+	}
+	return st, overrides, nil
+}
+
+// StashList retrieves the stashes of the branch, from the latest to the oldest one, with its changes
+func StashList(ctx context.Context, db *sqlx.DB, branchName integrity.BranchName) ([]*Stash, error) {
+	branch, err := BranchByName(ctx, db, branchName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find branch by name")
+	}
+	var stashes []*Stash
+	err = db.SelectContext(ctx, &stashes, `SELECT * FROM stashes WHERE branch_id=? ORDER BY id DESC`, branch.Id)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch stashes")
+	}
+	for _, st := range stashes {
+		st.Changes, err = uncommittedChanges(ctx, db, st.IndexId)
+		if err != nil {
+			return nil, errors.Wrap(err, "fetch stashed changes")
+		}
+	}
+	return stashes, nil
+}
+
+// StashDrop discards the stash with the given name (or the latest one), along with its changes
+func StashDrop(
+	ctx context.Context,
+	db *sqlx.DB,
+	branchName integrity.BranchName,
+	name string,
+) (*Stash, error) {
+	branch, err := BranchByName(ctx, db, branchName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find branch by name")
+	}
+	st, err := branch.stashByName(ctx, db, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "find stash by name")
+	}
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer tx.Rollback() // No-op once committed
+	_, err = tx.ExecContext(ctx, `DELETE FROM changes WHERE commit_id=0 AND index_id=?`, st.IndexId)
+	if err != nil {
+		return nil, errors.Wrap(err, "delete stashed changes from db")
+	}
+	err = dropStash(ctx, tx, st)
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "commit tx")
+	}
+	return st, nil
+}
+
+// dropStash deletes the stash and its index
+func dropStash(ctx context.Context, db sqlx.ExtContext, st *Stash) error {
+	err := store.DeleteFromDB(ctx, db, &Index{Id: st.IndexId})
+	if err != nil {
+		return errors.Wrap(err, "delete stash index from db")
+	}
+	err = store.DeleteFromDB(ctx, db, st)
+	if err != nil {
+		return errors.Wrap(err, "delete stash from db")
+	}
+	return nil
+}
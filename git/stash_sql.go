@@ -0,0 +1,32 @@
+package git
+
+// GetId wraps the id retrieval to implement Storable interface
+func (st *Stash) GetId() int64 {
+	return st.Id
+}
+
+// SetId wraps the id assignation to implement Storable interface
+func (st *Stash) SetId(id int64) {
+	st.Id = id
+}
+
+// SQLTable returns the sql SQLTable name of the entity
+//
+// Testing: tested by using naming conventions. See internal/name pkg
+func (st *Stash) SQLTable() string {
+	return "stashes"
+}
+
+// SQLColumns returns the SQLColumns each field represent on db
+// Notice the returned slice is the list of struct tags of exported fields
+// It's done to avoid reflection
+//
+// Testing: tested by using reflection at Columns_Test to check being the tags
+func (st *Stash) SQLColumns() []string {
+	return []string{
+		"id",
+		"branch_id",
+		"index_id",
+		"name",
+	}
+}
@@ -0,0 +1,43 @@
+package git
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gedex/inflector"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/internal/name"
+)
+
+func TestStashSQLColumns(t *testing.T) {
+	st := Stash{}
+	exclusions := []string{"Changes"}
+	typeOf := reflect.TypeOf(st)
+	var want []string
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		if isExcluded(exclusions, field.Name) {
+			continue
+		}
+		col := name.ToSnakeCase(field.Name)
+		want = append(want, col)
+	}
+	sort.Strings(want)
+
+	got := st.SQLColumns()
+	sort.Strings(got)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Stash.SQLColumns() mismatch (-want +got): %s", diff)
+	}
+}
+
+func TestStashSQLTable(t *testing.T) {
+	st := Stash{}
+	typeOf := reflect.TypeOf(st)
+	want := inflector.Pluralize(name.ToSnakeCase(typeOf.Name()))
+	got := st.SQLTable()
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Stash.SQLTable() mismatch (-want +got): %s", diff)
+	}
+}
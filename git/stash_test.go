@@ -0,0 +1,93 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/internal/test/assist"
+	"github.com/sebach1/rtc/schema"
+)
+
+func TestStashPop_foreignSchema(t *testing.T) {
+	project := &schema.Planisphere{gSchemas.Foo, gSchemas.Bar}
+	branch := gBranches.Foo.copy(t)
+	chg := gChanges.Bar.Update
+	db, mock := mockDBWithOptions(t)
+	(&assist.QueryStubber{
+		Expect: "SELECT * FROM branches WHERE name=?",
+		Rows: sqlmock.NewRows([]string{"id", "name", "schema", "index_id"}).
+			AddRow(branch.Id, branch.Name, gSchemas.Foo.Name, branch.IndexId),
+	}).Stub(mock)
+	(&assist.QueryStubber{
+		Expect: "SELECT * FROM stashes WHERE branch_id=? ORDER BY id DESC LIMIT 1",
+		Rows:   sqlmock.NewRows([]string{"id", "branch_id", "index_id", "name"}).AddRow(1, branch.Id, 2, "foo"),
+	}).Stub(mock)
+	(&assist.QueryStubber{
+		Expect: "SELECT * FROM changes WHERE commit_id=0 AND index_id=?",
+		Rows: sqlmock.NewRows([]string{"id", "table_name", "column_name", "entity_id", "index_id", "type", "value_type", "int_value"}).
+			AddRow(chg.Id, chg.TableName, chg.ColumnName, chg.EntityId, 2, chg.Type, chg.ValueType, chg.IntValue),
+	}).Stub(mock)
+
+	_, _, err := StashPop(context.Background(), db, project, integrity.BranchName(branch.Name), "")
+	wantErr := "the TABLE given does NOT BELONGS to the given SCHEMA"
+	if errMsg(err) != wantErr {
+		t.Errorf("StashPop() error = %v, wantErr %v", err, wantErr)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // Nothing is re-applied
+		t.Errorf("StashPop() unfulfilled expectations: %v", err)
+	}
+}
+
+func TestStashDrop(t *testing.T) {
+	branch := gBranches.Foo.copy(t)
+	tests := []struct {
+		name    string
+		stub    func(sqlmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "drops the stash along with its changes",
+			stub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				(&assist.ExecStubber{Expect: "DELETE FROM changes WHERE commit_id=0 AND index_id=?", Result: sqlmock.NewResult(0, 2)}).Stub(mock)
+				(&assist.ExecStubber{Expect: "DELETE FROM indices WHERE id=?", Result: sqlmock.NewResult(0, 1)}).Stub(mock)
+				(&assist.ExecStubber{Expect: "DELETE FROM stashes WHERE id=?", Result: sqlmock.NewResult(0, 1)}).Stub(mock)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "dropping FAILS rolls back the deleted changes",
+			stub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				(&assist.ExecStubber{Expect: "DELETE FROM changes WHERE commit_id=0 AND index_id=?", Result: sqlmock.NewResult(0, 2)}).Stub(mock)
+				(&assist.ExecStubber{Expect: "DELETE FROM indices WHERE id=?", Err: errFoo}).Stub(mock)
+				mock.ExpectRollback()
+			},
+			wantErr: errFoo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := mockDBWithOptions(t)
+			(&assist.QueryStubber{
+				Expect: "SELECT * FROM branches WHERE name=?",
+				Rows:   sqlmock.NewRows([]string{"id", "name", "index_id"}).AddRow(branch.Id, branch.Name, branch.IndexId),
+			}).Stub(mock)
+			(&assist.QueryStubber{
+				Expect: "SELECT * FROM stashes WHERE branch_id=? AND name=?",
+				Rows:   sqlmock.NewRows([]string{"id", "branch_id", "index_id", "name"}).AddRow(1, branch.Id, 2, "foo"),
+			}).Stub(mock)
+			tt.stub(mock)
+			_, err := StashDrop(context.Background(), db, integrity.BranchName(branch.Name), "foo")
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("StashDrop() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("StashDrop() unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package git
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sebach1/rtc/internal/store"
+)
+
+// A CommitState is a stage of the lifecycle of a commit
+type CommitState string
+
+const (
+	// Pending commits are waiting for an orchestration
+	Pending CommitState = "pending"
+	// Reviewing commits are being validated against the schema
+	Reviewing CommitState = "reviewing"
+	// Rejected commits didn't pass the review, or couldn't be delegated (e.g. its member is down)
+	Rejected CommitState = "rejected"
+	// Delegated commits were assigned to its reviewer, waiting to be delivered
+	Delegated CommitState = "delegated"
+	// InFlight commits are being delivered to its reviewer
+	InFlight CommitState = "in-flight"
+	// Merged commits were successfully delivered to its reviewer
+	Merged CommitState = "merged"
+	// Failed commits errored during its delivery, or were never delivered (e.g. its dependencies failed)
+	Failed CommitState = "failed"
+	// Reverted commits were undone after being merged. See Commit.Inverse
+	Reverted CommitState = "reverted"
+)
+
+// stateTransitions are the states each state can transition to
+// Notice the unfinished states can go back to Pending, in order to resume an interrupted orchestration
+var stateTransitions = map[CommitState][]CommitState{
+	Pending:   {Reviewing},
+	Reviewing: {Rejected, Delegated, Pending},
+	Rejected:  {Pending},
+	Delegated: {InFlight, Failed, Pending},
+	InFlight:  {Merged, Failed, Pending},
+	Merged:    {Reverted},
+	Failed:    {Pending},
+	Reverted:  nil,
+}
+
+// Validate checks the state is a known one
+func (state CommitState) Validate() error {
+	if _, ok := stateTransitions[state]; !ok {
+		return errUnknownCommitState
+	}
+	return nil
+}
+
+// canTransition checks if the state can transition to the given one
+func (state CommitState) canTransition(to CommitState) bool {
+	for _, allowed := range stateTransitions[state] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// A Transition is a change of the state of a commit
+type Transition struct {
+	Id        int64       `json:"id,omitempty"`
+	CommitId  int64       `json:"commit_id,omitempty"`
+	FromState CommitState `json:"from_state,omitempty"`
+	ToState   CommitState `json:"to_state,omitempty"`
+	At        time.Time   `json:"at,omitempty"`
+}
+
+// Is checks if the commit is in any of the given states
+// Notice a commit with a zero-valued state is Pending
+func (comm *Commit) Is(states ...CommitState) bool {
+	current := comm.State
+	if current == "" {
+		current = Pending
+	}
+	for _, state := range states {
+		if current == state {
+			return true
+		}
+	}
+	return false
+}
+
+// transition changes the state of the commit, recording it on its .Transitions
+// It returns errInvalidTransition if the current state can't transition to the given one
+func (comm *Commit) transition(to CommitState) error {
+	from := comm.State
+	if from == "" {
+		from = Pending
+	}
+	if !from.canTransition(to) {
+		return errInvalidTransition
+	}
+	comm.State = to
+	comm.Transitions = append(comm.Transitions, &Transition{CommitId: comm.Id, FromState: from, ToState: to, At: time.Now()})
+	return nil
+}
+
+// settle transitions the delivered commit to Merged, or to Failed if its delivery errored
+func (comm *Commit) settle(err error) {
+	if err != nil {
+		_ = comm.transition(Failed)
+		return
+	}
+	_ = comm.transition(Merged)
+}
+
+// restart brings the commit back to Pending if it was left by a previous orchestration
+func (comm *Commit) restart() {
+	if comm.Is(Rejected, Failed, Reviewing, Delegated, InFlight) {
+		_ = comm.transition(Pending)
+	}
+}
+
+// storeTransitions persists the not yet stored transitions of the given commits
+func storeTransitions(ctx context.Context, db *sqlx.DB, comms ...*Commit) error {
+	var transitions []store.Storable
+	for _, comm := range comms {
+		for _, tr := range comm.Transitions {
+			if tr.Id != 0 {
+				continue
+			}
+			tr.CommitId = comm.Id
+			transitions = append(transitions, tr)
+		}
+	}
+	if len(transitions) == 0 {
+		return nil
+	}
+	return store.InsertIntoDB(ctx, db, transitions...)
+}
+
+// fetchCommitsTransitions retrieves the transitions of all the given commits in a single query
+// and assigns them to the .Transitions field of its belonging commit
+func fetchCommitsTransitions(ctx context.Context, db *sqlx.DB, comms []*Commit) error {
+	if len(comms) == 0 {
+		return nil
+	}
+	commsById := make(map[int64]*Commit, len(comms))
+	var ids []int64
+	for _, comm := range comms {
+		commsById[comm.Id] = comm
+		ids = append(ids, comm.Id)
+	}
+
+	qr, args, err := sqlx.In(`SELECT * FROM transitions WHERE commit_id IN (?) ORDER BY id`, ids)
+	if err != nil {
+		return err
+	}
+	var transitions []*Transition
+	err = db.SelectContext(ctx, &transitions, qr, args...)
+	if err != nil {
+		return err
+	}
+	for _, tr := range transitions {
+		comm, ok := commsById[tr.CommitId]
+		if !ok {
+			continue
+		}
+		comm.Transitions = append(comm.Transitions, tr)
+	}
+	return nil
+}
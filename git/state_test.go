@@ -0,0 +1,158 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/internal/test/assist"
+	"github.com/sebach1/rtc/internal/test/thelper"
+)
+
+func TestCommit_transition(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		from     CommitState
+		path     []CommitState
+		want     CommitState
+		wantQtTr int
+		wantErr  error
+	}{
+		{
+			name:     "ZERO state is pending",
+			path:     []CommitState{Reviewing, Delegated, InFlight, Merged},
+			want:     Merged,
+			wantQtTr: 4,
+		},
+		{
+			name:     "merged commit is REVERTED",
+			from:     Merged,
+			path:     []CommitState{Reverted},
+			want:     Reverted,
+			wantQtTr: 1,
+		},
+		{
+			name:     "failed commit is RESTARTED",
+			from:     Failed,
+			path:     []CommitState{Pending, Reviewing},
+			want:     Reviewing,
+			wantQtTr: 2,
+		},
+		{
+			name:    "pending commit CANNOT be MERGED",
+			path:    []CommitState{Merged},
+			want:    Pending,
+			wantErr: errInvalidTransition,
+		},
+		{
+			name:    "rejected commit CANNOT be DELIVERED",
+			from:    Rejected,
+			path:    []CommitState{InFlight},
+			want:    Rejected,
+			wantErr: errInvalidTransition,
+		},
+		{
+			name:    "reverted commit CANNOT be RESTARTED",
+			from:    Reverted,
+			path:    []CommitState{Pending},
+			want:    Reverted,
+			wantErr: errInvalidTransition,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			comm := &Commit{Id: 1, State: tt.from}
+			var err error
+			for _, to := range tt.path {
+				err = comm.transition(to)
+				if err != nil {
+					break
+				}
+			}
+			if err != tt.wantErr {
+				t.Errorf("Commit.transition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !comm.Is(tt.want) {
+				t.Errorf("Commit.transition() state = %v, want %v", comm.State, tt.want)
+			}
+			if len(comm.Transitions) != tt.wantQtTr {
+				t.Errorf("Commit.transition() transitions qt = %v, want %v", len(comm.Transitions), tt.wantQtTr)
+			}
+			for _, tr := range comm.Transitions {
+				if tr.CommitId != comm.Id || tr.At.IsZero() {
+					t.Errorf("Commit.transition() transition %+v is NOT BOUND to the commit or NOT TIMESTAMPED", tr)
+				}
+			}
+		})
+	}
+}
+
+func TestCommitState_Validate(t *testing.T) {
+	t.Parallel()
+	for state := range stateTransitions {
+		if err := state.Validate(); err != nil {
+			t.Errorf("CommitState.Validate() of %v error = %v", state, err)
+		}
+	}
+	if err := CommitState("foo").Validate(); err != errUnknownCommitState {
+		t.Errorf("CommitState.Validate() error = %v, wantErr %v", err, errUnknownCommitState)
+	}
+}
+
+func TestBranch_CommitsByState(t *testing.T) {
+	commCols := []string{"id", "branch_id", "state"}
+	tests := []struct {
+		name    string
+		states  []CommitState
+		stubs   []*assist.QueryStubber
+		want    []*Commit
+		wantErr error
+	}{
+		{
+			name:   "retrieves the commits in ANY of the states",
+			states: []CommitState{Pending, Failed},
+			stubs: []*assist.QueryStubber{
+				{
+					Expect: "SELECT * FROM commits WHERE branch_id=? AND state IN (?, ?)",
+					Rows:   sqlmock.NewRows(commCols).AddRow(1, gBranches.Foo.Id, Pending).AddRow(2, gBranches.Foo.Id, Failed),
+				},
+			},
+			want: []*Commit{
+				{Id: 1, BranchId: gBranches.Foo.Id, State: Pending},
+				{Id: 2, BranchId: gBranches.Foo.Id, State: Failed},
+			},
+		},
+		{
+			name:    "UNKNOWN state given",
+			states:  []CommitState{"foo"},
+			wantErr: errUnknownCommitState,
+		},
+		{
+			name:    "query returns ERR",
+			states:  []CommitState{Merged},
+			stubs:   []*assist.QueryStubber{{Expect: "SELECT * FROM commits", Err: errFoo}},
+			wantErr: errFoo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := thelper.MockDB(t)
+			for _, stub := range tt.stubs {
+				stub.Stub(mock)
+			}
+			got, err := gBranches.Foo.copy(t).CommitsByState(context.Background(), db, tt.states...)
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("Branch.CommitsByState() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Branch.CommitsByState() mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package git
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/schema"
+)
+
+// A BranchStatus summarizes the pending work of a branch, as git-status does: its uncommitted changes
+// and the changes of its unmerged commits, grouped by schema, table and entity
+type BranchStatus struct {
+	Branch  integrity.BranchName `json:"branch,omitempty"`
+	Schemas []*SchemaStatus      `json:"schemas,omitempty"`
+
+	// Uncommitted and Unmerged are the qt of uncommitted changes and unmerged commits of the branch
+	Uncommitted int `json:"uncommitted,omitempty"`
+	Unmerged    int `json:"unmerged,omitempty"`
+	// Errored is the qt of unmerged commits which were rejected or failed, awaiting to be retried
+	Errored int `json:"errored,omitempty"`
+	// Invalid is the qt of changes which doesn't pass its validation against the project
+	Invalid int `json:"invalid,omitempty"`
+}
+
+// A SchemaStatus groups the pending changes over the tables of a schema
+// Notice the changes whose table doesn't belong to any schema of the project are grouped without name
+type SchemaStatus struct {
+	Name   integrity.SchemaName `json:"name,omitempty"`
+	Tables []*TableStatus       `json:"tables,omitempty"`
+}
+
+// A TableStatus groups the pending changes over the entities of a table
+type TableStatus struct {
+	Name integrity.TableName `json:"name,omitempty"`
+	// Types is the qt of changes by its type
+	Types    map[integrity.CRUD]int `json:"types,omitempty"`
+	Entities []*EntityStatus        `json:"entities,omitempty"`
+}
+
+// An EntityStatus groups the pending changes over an entity
+// Notice the creations without placeholder are grouped without id. See integrity.Id.IsTemporary
+type EntityStatus struct {
+	Id      integrity.Id    `json:"id,omitempty"`
+	Changes []*ChangeStatus `json:"changes,omitempty"`
+}
+
+// A ChangeStatus describes a pending change and whether it's valid against the project
+type ChangeStatus struct {
+	Change *Change `json:"change,omitempty"`
+	// State is the state of the commit of the change, which is empty if it's uncommitted
+	State CommitState `json:"state,omitempty"`
+
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// schemaOfTable retrieves the schema of the project the table belongs to
+func schemaOfTable(project *schema.Planisphere, tableName integrity.TableName) (*schema.Schema, error) {
+	if project == nil {
+		return nil, errNilProject
+	}
+	return project.SchemaOfTable(tableName)
+}
+
+// validateChange checks the change is consistent and valid against the schema of its table, which
+// couldn't be resolved in case of schErr
+func validateChange(project *schema.Planisphere, sch *schema.Schema, schErr error, chg *Change) error {
+	err := chg.Validate() // Classifies its type
+	if err != nil {
+		return err
+	}
+	if schErr != nil {
+		return schErr
+	}
+	var wg sync.WaitGroup
+	errCh := make(chan error, 1)
+	wg.Add(1)
+	sch.ValidateCtx(chg.TableName, chg.ColumnName, chg.Options.Keys(), chg.Value(), project, &wg, errCh)
+	wg.Wait()
+	close(errCh)
+	return <-errCh
+}
+
+// schemaOf retrieves the status of the schema with the given name, adding it in case it isn't yet
+func (st *BranchStatus) schemaOf(schName integrity.SchemaName) *SchemaStatus {
+	for _, schSt := range st.Schemas {
+		if schSt.Name == schName {
+			return schSt
+		}
+	}
+	schSt := &SchemaStatus{Name: schName}
+	st.Schemas = append(st.Schemas, schSt)
+	return schSt
+}
+
+// tableOf retrieves the status of the table with the given name, adding it in case it isn't yet
+func (schSt *SchemaStatus) tableOf(tableName integrity.TableName) *TableStatus {
+	for _, tableSt := range schSt.Tables {
+		if tableSt.Name == tableName {
+			return tableSt
+		}
+	}
+	tableSt := &TableStatus{Name: tableName, Types: make(map[integrity.CRUD]int)}
+	schSt.Tables = append(schSt.Tables, tableSt)
+	return tableSt
+}
+
+// entityOf retrieves the status of the entity with the given id, adding it in case it isn't yet
+func (tableSt *TableStatus) entityOf(id integrity.Id) *EntityStatus {
+	for _, entSt := range tableSt.Entities {
+		if entSt.Id == id {
+			return entSt
+		}
+	}
+	entSt := &EntityStatus{Id: id}
+	tableSt.Entities = append(tableSt.Entities, entSt)
+	return entSt
+}
+
+// add classifies the change, which belongs to the given commit (if any), onto the status
+func (st *BranchStatus) add(project *schema.Planisphere, chg *Change, comm *Commit) {
+	chgSt := &ChangeStatus{Change: chg, Valid: true}
+	if comm != nil {
+		chgSt.State = comm.State
+		if chgSt.State == "" {
+			chgSt.State = Pending
+		}
+	}
+	sch, schErr := schemaOfTable(project, chg.TableName)
+	err := validateChange(project, sch, schErr, chg)
+	if err != nil {
+		chgSt.Valid, chgSt.Error = false, err.Error()
+		st.Invalid++
+	}
+
+	var schName integrity.SchemaName
+	if schErr == nil {
+		schName = sch.Name
+	}
+	tableSt := st.schemaOf(schName).tableOf(chg.TableName)
+	tableSt.Types[chg.Type]++
+	entSt := tableSt.entityOf(chg.EntityId)
+	entSt.Changes = append(entSt.Changes, chgSt)
+}
+
+// newBranchStatus summarizes the given uncommitted changes and unmerged commits
+func newBranchStatus(project *schema.Planisphere, chgs []*Change, comms []*Commit) *BranchStatus {
+	st := &BranchStatus{Uncommitted: len(chgs), Unmerged: len(comms)}
+	for _, chg := range chgs {
+		st.add(project, chg, nil)
+	}
+	for _, comm := range comms {
+		if comm.Is(Rejected, Failed) {
+			st.Errored++
+		}
+		for _, chg := range comm.Changes {
+			st.add(project, chg, comm)
+		}
+	}
+	return st
+}
+
+// Status retrieves the summary of the pending work of the branch, validated against the given project
+func (b *Branch) Status(ctx context.Context, db *sqlx.DB, project *schema.Planisphere) (*BranchStatus, error) {
+	chgs, comms, err := b.pendingChanges(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	st := newBranchStatus(project, chgs, comms)
+	st.Branch = integrity.BranchName(b.Name)
+	return st, nil
+}
+
+// Status retrieves the summary of the pending work of the given branch. See Branch.Status
+func Status(
+	ctx context.Context,
+	db *sqlx.DB,
+	project *schema.Planisphere,
+	branchName integrity.BranchName,
+) (*BranchStatus, error) {
+	branch, err := BranchByName(ctx, db, branchName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find branch by name")
+	}
+	st, err := branch.Status(ctx, db, project)
+	if err != nil {
+		return nil, errors.Wrap(err, "branch status")
+	}
+	return st, nil
+}
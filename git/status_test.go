@@ -0,0 +1,55 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/schema"
+)
+
+func Test_newBranchStatus(t *testing.T) {
+	t.Parallel()
+	chgs := []*Change{gChanges.Foo.Update.copy(t), gChanges.Bar.Update.copy(t)}
+	comms := []*Commit{
+		{Id: 1, State: Failed, Changes: []*Change{gChanges.Foo.Create.copy(t)}},
+		{Id: 2, Changes: []*Change{gChanges.Foo.Update.copy(t)}},
+	}
+	got := newBranchStatus(&schema.Planisphere{gSchemas.Foo}, chgs, comms)
+
+	if got.Uncommitted != 2 || got.Unmerged != 2 || got.Errored != 1 || got.Invalid != 1 {
+		t.Errorf("newBranchStatus() counts: uncommitted %v, unmerged %v, errored %v, invalid %v",
+			got.Uncommitted, got.Unmerged, got.Errored, got.Invalid)
+	}
+	var gotSchemas []integrity.SchemaName
+	for _, schSt := range got.Schemas {
+		gotSchemas = append(gotSchemas, schSt.Name)
+	}
+	if diff := cmp.Diff([]integrity.SchemaName{gSchemas.Foo.Name, ""}, gotSchemas); diff != "" {
+		t.Fatalf("newBranchStatus() schemas mismatch (-want +got): %s", diff)
+	}
+
+	fooTable := got.Schemas[0].Tables[0]
+	if diff := cmp.Diff(map[integrity.CRUD]int{"update": 2, "create": 1}, fooTable.Types); diff != "" {
+		t.Errorf("newBranchStatus() types mismatch (-want +got): %s", diff)
+	}
+	var gotStates []CommitState
+	var gotValid []bool
+	for _, entSt := range fooTable.Entities {
+		for _, chgSt := range entSt.Changes {
+			gotStates = append(gotStates, chgSt.State)
+			gotValid = append(gotValid, chgSt.Valid)
+		}
+	}
+	if diff := cmp.Diff([]CommitState{"", Pending, Failed}, gotStates); diff != "" {
+		t.Errorf("newBranchStatus() states mismatch (-want +got): %s", diff)
+	}
+	if diff := cmp.Diff([]bool{true, true, true}, gotValid); diff != "" {
+		t.Errorf("newBranchStatus() validity mismatch (-want +got): %s", diff)
+	}
+
+	barChg := got.Schemas[1].Tables[0].Entities[0].Changes[0]
+	if barChg.Valid || barChg.Error == "" {
+		t.Errorf("newBranchStatus() change of a table WITHOUT SCHEMA is valid")
+	}
+}
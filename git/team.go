@@ -1,6 +1,8 @@
 package git
 
 import (
+	"time"
+
 	"github.com/sebach1/rtc/integrity"
 )
 
@@ -8,6 +10,17 @@ import (
 type Team struct {
 	AssignedSchema integrity.SchemaName `json:"assigned_schema,omitempty"`
 	Members        []*Member            `json:"members,omitempty"`
+
+	// RetryPolicy is the policy applied to the calls of the members which doesn't define its own
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+	// Timeout limits the delivery of each commit to the members which doesn't define its own,
+	// including all of its attempts. Zero means no limit
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// RateLimit throttles the calls to all the members, as they work for the same schema
+	RateLimit *RateLimit `json:"rate_limit,omitempty"`
+
+	// Strategy is the way the team chooses among the members assigned to the same table. See Team.AddReplica
+	Strategy SelectionStrategy `json:"strategy,omitempty"`
 }
 
 // AddMember validates if a member with the provided args can be created and then adds it to the team
@@ -31,14 +44,13 @@ func (t *Team) AddMember(tableName integrity.TableName, collab Collaborator, for
 }
 
 // Delegate retrieves the Collaborator which can perform actions over the given tableName
+// When many members are assigned to the table, it's chosen by the .Strategy
+// While the circuit breaker of a member is open, it's replaced by its fallback. See Member.Breaker
+// Notice the StrategyRoundRobin takes turns by the commits, so the first member is retrieved
 func (t *Team) Delegate(tableName integrity.TableName) (Collaborator, error) {
-	for _, member := range t.Members {
-		if member.AssignedTable == tableName {
-			if member.Collab == nil {
-				return nil, errNoCollaborators
-			}
-			return member.Collab, nil
-		}
+	delegations, err := t.delegations(tableName, 0)
+	if err != nil {
+		return nil, err
 	}
-	return nil, errNoMembers
+	return delegations[0].Collab, nil
 }
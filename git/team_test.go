@@ -2,6 +2,7 @@ package git
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sebach1/rtc/integrity"
@@ -55,3 +56,46 @@ func TestTeam_Delegate(t *testing.T) {
 		})
 	}
 }
+
+func TestTeam_delegation(t *testing.T) {
+	t.Parallel()
+	teamPolicy, memberPolicy := &RetryPolicy{MaxAttempts: 2}, &RetryPolicy{MaxAttempts: 5}
+	teamLimit, memberLimit := &RateLimit{Rate: 1}, &RateLimit{Rate: 2}
+	team := &Team{RetryPolicy: teamPolicy, Timeout: time.Second, RateLimit: teamLimit}
+	tests := []struct {
+		name   string
+		member *Member
+		want   *Delegation
+	}{
+		{
+			name:   "MEMBER settings override the team ones",
+			member: &Member{RetryPolicy: memberPolicy, Timeout: time.Minute, MaxConcurrency: 3, RateLimit: memberLimit},
+			want: &Delegation{RetryPolicy: memberPolicy, Timeout: time.Minute, MaxConcurrency: 3,
+				RateLimits: []*RateLimit{teamLimit, memberLimit}},
+		},
+		{
+			name:   "member WITHOUT settings takes the team ones",
+			member: &Member{},
+			want:   &Delegation{RetryPolicy: teamPolicy, Timeout: time.Second, RateLimits: []*RateLimit{teamLimit}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := team.delegation(tt.member, nil)
+			if got.RetryPolicy != tt.want.RetryPolicy || got.Timeout != tt.want.Timeout ||
+				got.MaxConcurrency != tt.want.MaxConcurrency {
+				t.Errorf("Team.delegation() = %+v, want %+v", got, tt.want)
+			}
+			if len(got.RateLimits) != len(tt.want.RateLimits) {
+				t.Fatalf("Team.delegation() rate limits = %v, want %v", got.RateLimits, tt.want.RateLimits)
+			}
+			for i, limit := range got.RateLimits {
+				if limit != tt.want.RateLimits[i] {
+					t.Errorf("Team.delegation() rate limits = %v, want %v", got.RateLimits, tt.want.RateLimits)
+				}
+			}
+		})
+	}
+}
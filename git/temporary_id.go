@@ -0,0 +1,112 @@
+package git
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/internal/store"
+)
+
+// TemporaryId relates the local placeholder of an entity (see integrity.Id.IsTemporary) with the id
+// returned by its collaborator once it was created
+type TemporaryId struct {
+	Id       int64 `json:"id,omitempty"`
+	BranchId int64 `json:"branch_id,omitempty"`
+
+	Placeholder integrity.Id `json:"placeholder,omitempty"`
+	EntityId    integrity.Id `json:"entity_id,omitempty"`
+}
+
+// placeholders relates each create commit of the pull request with the temporary id of the entity it creates
+// Notice it must be computed before the merge, as the collaborator response replaces the placeholders
+func (pR *PullRequest) placeholders() map[*Commit]integrity.Id {
+	placeholders := make(map[*Commit]integrity.Id)
+	for _, comm := range pR.Commits {
+		if commType, _ := comm.Type(); commType != "create" {
+			continue
+		}
+		for _, chg := range comm.Changes {
+			if chg.EntityId.IsTemporary() {
+				placeholders[comm] = chg.EntityId
+				break
+			}
+		}
+	}
+	return placeholders
+}
+
+// temporaryRefs retrieves the temporary ids used by the changes of the commit, excluding the one it creates
+func (comm *Commit) temporaryRefs() (ids []integrity.Id) {
+	commType, _ := comm.Type()
+	for _, chg := range comm.Changes {
+		if chg.EntityId.IsTemporary() && commType != "create" {
+			ids = append(ids, chg.EntityId)
+		}
+		if chg.ValueType != "string" {
+			continue
+		}
+		if id := integrity.Id(chg.StringValue); id.IsTemporary() {
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
+// resolveTemporaryIds substitutes the placeholders used by the change by its resolved ids
+func (chg *Change) resolveTemporaryIds(resolved map[integrity.Id]integrity.Id) {
+	if id, ok := resolved[chg.EntityId]; ok && !id.IsNil() {
+		chg.EntityId = id
+	}
+	if chg.ValueType != "string" {
+		return
+	}
+	if id, ok := resolved[integrity.Id(chg.StringValue)]; ok && !id.IsNil() {
+		chg.StringValue = string(id)
+	}
+}
+
+// TemporaryIds retrieves the placeholders already resolved on the branch, keyed by its temporary ids
+func (b *Branch) TemporaryIds(ctx context.Context, db *sqlx.DB) (map[integrity.Id]integrity.Id, error) {
+	var tmpIds []*TemporaryId
+	err := db.SelectContext(ctx, &tmpIds, `SELECT * FROM temporary_ids WHERE branch_id=?`, b.Id)
+	if err != nil {
+		return nil, err
+	}
+	resolved := make(map[integrity.Id]integrity.Id, len(tmpIds))
+	for _, tmpId := range tmpIds {
+		resolved[tmpId.Placeholder] = tmpId.EntityId
+	}
+	return resolved, nil
+}
+
+// storeTemporaryIds persists the resolution of the placeholders created by the merged commits, and
+// rewrites the changes of the branch which still use them
+func storeTemporaryIds(
+	ctx context.Context,
+	db *sqlx.DB,
+	branch *Branch,
+	placeholders map[*Commit]integrity.Id,
+) error {
+	for comm, placeholder := range placeholders {
+		entityId := comm.entityId()
+		if !comm.Is(Merged) || entityId.IsNil() {
+			continue
+		}
+		err := store.InsertIntoDB(ctx, db, &TemporaryId{BranchId: branch.Id, Placeholder: placeholder, EntityId: entityId})
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx,
+			`UPDATE changes SET entity_id=? WHERE entity_id=? AND index_id=?`, entityId, placeholder, branch.IndexId)
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx,
+			`UPDATE changes SET string_value=? WHERE string_value=? AND index_id=?`, entityId, placeholder, branch.IndexId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package git
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gedex/inflector"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/internal/name"
+)
+
+func TestTemporaryIdSQLColumns(t *testing.T) {
+	tmpId := TemporaryId{}
+	exclusions := []string{}
+	typeOf := reflect.TypeOf(tmpId)
+	var want []string
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		if isExcluded(exclusions, field.Name) {
+			continue
+		}
+		col := name.ToSnakeCase(field.Name)
+		want = append(want, col)
+	}
+	sort.Strings(want)
+
+	got := tmpId.SQLColumns()
+	sort.Strings(got)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("TemporaryId.SQLColumns() mismatch (-want +got): %s", diff)
+	}
+}
+
+func TestTemporaryIdSQLTable(t *testing.T) {
+	tmpId := TemporaryId{}
+	typeOf := reflect.TypeOf(tmpId)
+	want := inflector.Pluralize(name.ToSnakeCase(typeOf.Name()))
+	got := tmpId.SQLTable()
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("TemporaryId.SQLTable() mismatch (-want +got): %s", diff)
+	}
+}
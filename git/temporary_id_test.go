@@ -0,0 +1,154 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/integrity"
+	"github.com/sebach1/rtc/internal/test/assist"
+	"github.com/sebach1/rtc/internal/test/thelper"
+)
+
+func TestBranch_TemporaryIds(t *testing.T) {
+	tmpIdCols := []string{"id", "branch_id", "placeholder", "entity_id"}
+	tests := []struct {
+		name    string
+		branch  *Branch
+		stub    *assist.QueryStubber
+		want    map[integrity.Id]integrity.Id
+		wantErr error
+	}{
+		{
+			name:    "query returns ERR",
+			branch:  gBranches.Foo.copy(t),
+			stub:    &assist.QueryStubber{Expect: "SELECT * FROM temporary_ids WHERE branch_id=?", Err: errFoo},
+			wantErr: errFoo,
+		},
+		{
+			name:   "relates the placeholders with its entity ids",
+			branch: gBranches.Foo.copy(t),
+			stub: &assist.QueryStubber{
+				Expect: "SELECT * FROM temporary_ids WHERE branch_id=?",
+				Rows: sqlmock.NewRows(tmpIdCols).
+					AddRow(1, gBranches.Foo.Id, "tmp:foo", "fooId").
+					AddRow(2, gBranches.Foo.Id, "tmp:bar", "barId"),
+			},
+			want: map[integrity.Id]integrity.Id{"tmp:foo": "fooId", "tmp:bar": "barId"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := thelper.MockDB(t)
+			tt.stub.Stub(mock)
+			got, err := tt.branch.TemporaryIds(context.Background(), db)
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("Branch.TemporaryIds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Branch.TemporaryIds() mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestChange_resolveTemporaryIds(t *testing.T) {
+	t.Parallel()
+	resolved := map[integrity.Id]integrity.Id{"tmp:foo": "fooId"}
+	tests := []struct {
+		name         string
+		entityId     integrity.Id
+		val          interface{}
+		wantEntityId integrity.Id
+		wantVal      interface{}
+	}{
+		{name: "RESOLVED entity", entityId: "tmp:foo", val: "bar", wantEntityId: "fooId", wantVal: "bar"},
+		{name: "RESOLVED value", entityId: "bar", val: "tmp:foo", wantEntityId: "bar", wantVal: "fooId"},
+		{name: "UNRESOLVED entity", entityId: "tmp:bar", val: "bar", wantEntityId: "tmp:bar", wantVal: "bar"},
+		{name: "NOT STRING value", entityId: "bar", val: 3, wantEntityId: "bar", wantVal: 3},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			chg := gChanges.Foo.Update.copy(t)
+			chg.EntityId = tt.entityId
+			err := chg.SetValue(tt.val)
+			if err != nil {
+				t.Fatal(err)
+			}
+			chg.resolveTemporaryIds(resolved)
+			if chg.EntityId != tt.wantEntityId {
+				t.Errorf("Change.resolveTemporaryIds() entity id = %v, want %v", chg.EntityId, tt.wantEntityId)
+			}
+			if diff := cmp.Diff(tt.wantVal, chg.Value()); diff != "" {
+				t.Errorf("Change.resolveTemporaryIds() value mismatch (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func Test_storeTemporaryIds(t *testing.T) {
+	created := func(state CommitState, entityId integrity.Id) *Commit {
+		chg := gChanges.Foo.Create.copy(t)
+		chg.EntityId = entityId
+		return &Commit{State: state, Changes: []*Change{chg}}
+	}
+	tests := []struct {
+		name      string
+		comm      *Commit
+		qrStubs   []*assist.QueryStubber
+		execStubs []*assist.ExecStubber
+		wantErr   error
+	}{
+		{
+			name: "UNMERGED commit is NOT stored",
+			comm: created(Failed, "fooId"),
+		},
+		{
+			name: "merged WITHOUT returned entity is NOT stored",
+			comm: created(Merged, "tmp:foo"),
+		},
+		{
+			name: "stores the resolution and rewrites the branch changes",
+			comm: created(Merged, "fooId"),
+			qrStubs: []*assist.QueryStubber{
+				{Expect: "INSERT INTO temporary_ids", Rows: sqlmock.NewRows([]string{"id"}).AddRow(1)},
+			},
+			execStubs: []*assist.ExecStubber{
+				{Expect: "UPDATE changes SET entity_id=? WHERE entity_id=? AND index_id=?", Result: sqlmock.NewResult(0, 2)},
+				{Expect: "UPDATE changes SET string_value=? WHERE string_value=? AND index_id=?", Result: sqlmock.NewResult(0, 0)},
+			},
+		},
+		{
+			name: "insertion returns ERR",
+			comm: created(Merged, "fooId"),
+			qrStubs: []*assist.QueryStubber{
+				{Expect: "INSERT INTO temporary_ids", Err: errFoo},
+			},
+			wantErr: errFoo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := thelper.MockDB(t)
+			for _, stub := range tt.qrStubs {
+				stub.Stub(mock)
+			}
+			for _, stub := range tt.execStubs {
+				stub.Stub(mock)
+			}
+			placeholders := map[*Commit]integrity.Id{tt.comm: "tmp:foo"}
+			err := storeTemporaryIds(context.Background(), db, gBranches.Foo.copy(t), placeholders)
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("storeTemporaryIds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("storeTemporaryIds() unmet expectations: %v", err)
+			}
+		})
+	}
+}
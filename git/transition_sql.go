@@ -0,0 +1,33 @@
+package git
+
+// GetId wraps the id retrieval to implement Storable interface
+func (tr *Transition) GetId() int64 {
+	return tr.Id
+}
+
+// SetId wraps the id assignation to implement Storable interface
+func (tr *Transition) SetId(id int64) {
+	tr.Id = id
+}
+
+// SQLTable returns the sql SQLTable name of the entity
+//
+// Testing: tested by using naming conventions. See internal/name pkg
+func (tr *Transition) SQLTable() string {
+	return "transitions"
+}
+
+// SQLColumns returns the SQLColumns each field represent on db
+// Notice the returned slice is the list of struct tags of exported fields
+// It's done to avoid reflection
+//
+// Testing: tested by using reflection at Columns_Test to check being the tags
+func (tr *Transition) SQLColumns() []string {
+	return []string{
+		"id",
+		"commit_id",
+		"from_state",
+		"to_state",
+		"at",
+	}
+}
@@ -0,0 +1,43 @@
+package git
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gedex/inflector"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/internal/name"
+)
+
+func TestTransitionSQLColumns(t *testing.T) {
+	tr := Transition{}
+	exclusions := []string{}
+	typeOf := reflect.TypeOf(tr)
+	var want []string
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+		if isExcluded(exclusions, field.Name) {
+			continue
+		}
+		col := name.ToSnakeCase(field.Name)
+		want = append(want, col)
+	}
+	sort.Strings(want)
+
+	got := tr.SQLColumns()
+	sort.Strings(got)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Transition.SQLColumns() mismatch (-want +got): %s", diff)
+	}
+}
+
+func TestTransitionSQLTable(t *testing.T) {
+	tr := Transition{}
+	typeOf := reflect.TypeOf(tr)
+	want := inflector.Pluralize(name.ToSnakeCase(typeOf.Name()))
+	got := tr.SQLTable()
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Transition.SQLTable() mismatch (-want +got): %s", diff)
+	}
+}
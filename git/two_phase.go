@@ -0,0 +1,175 @@
+package git
+
+import (
+	"context"
+
+	"github.com/sebach1/rtc/internal/xerrors"
+)
+
+// canTwoPhase checks if all the reviewers of the pull request are Preparers
+func (pR *PullRequest) canTwoPhase() bool {
+	if len(pR.Commits) == 0 {
+		return false
+	}
+	for _, comm := range pR.Commits {
+		if comm.Reviewer == nil {
+			if comm.Is(Rejected) {
+				continue
+			}
+			return false
+		}
+		if _, ok := comm.Reviewer.(Preparer); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeTwoPhase merges the pull request by a two-phase commit: it prepares every commit
+// and only in case all of them are prepared, it commits them following its dependencies
+// Otherwise, all of them are aborted, as well as the prepared ones which aren't committed as its
+// dependencies failed or the orchestration was canceled
+// Notice any rejected commit aborts the entire pull request before the preparation
+func (own *Owner) mergeTwoPhase(ctx context.Context, pR *PullRequest) {
+	for _, comm := range pR.Commits {
+		if comm.Is(Rejected) {
+			own.abortTwoPhase(ctx, pR, nil)
+			return
+		}
+	}
+
+	prepareErrs := make([]error, len(pR.Commits))
+	keys := make([]string, len(pR.Commits))
+	forEach(own.deliveryWorkers(), len(pR.Commits), func(i int) {
+		comm := pR.Commits[i]
+		keys[i] = comm.IdempotencyKey()
+		prepareErrs[i] = comm.transition(InFlight)
+		if prepareErrs[i] != nil {
+			return
+		}
+		applied, err := own.applied(ctx, comm, keys[i])
+		if err != nil || applied { // The applied ones are neither prepared nor aborted
+			if applied {
+				_ = comm.transition(Merged)
+			}
+			prepareErrs[i] = err
+			return
+		}
+		ctx, cancel := comm.withTimeout(WithIdempotencyKey(ctx, keys[i]))
+		defer cancel()
+		release, err := own.limiter.acquire(ctx, comm)
+		if err != nil {
+			prepareErrs[i] = err
+			return
+		}
+		defer release()
+		// Notice only the preparation goes through the circuit breaker, as the prepared commits must be committed
+		prepareErrs[i] = comm.Breaker.do(func() (err error) {
+			comm.Attempts, err = comm.RetryPolicy.do(ctx, comm.RateLimits, func(ctx context.Context) error {
+				err := comm.Reviewer.Init(ctx)
+				if err != nil {
+					return err
+				}
+				return comm.Reviewer.(Preparer).Prepare(ctx, comm)
+			})
+			return
+		})
+	})
+
+	if ctx.Err() != nil { // Canceled before committing
+		own.abortTwoPhase(ctx, pR, prepareErrs)
+		return
+	}
+	for _, err := range prepareErrs {
+		if err != nil {
+			own.abortTwoPhase(ctx, pR, prepareErrs)
+			return
+		}
+	}
+
+	idxs := make(map[*Commit]int, len(pR.Commits))
+	for i, comm := range pR.Commits {
+		idxs[comm] = i
+	}
+	attempted := make([]bool, len(pR.Commits)) // Written only by the worker of the commit
+	own.mergeDAG(ctx, pR, func(comm *Commit) error {
+		attempted[idxs[comm]] = true
+		if comm.Is(Merged) {
+			own.report(resultOf(comm, nil))
+			return nil
+		}
+		res := newResult(comm)
+		res.IdempotencyKey = keys[idxs[comm]]
+		ctx := WithIdempotencyKey(ctx, res.IdempotencyKey)
+		own.emit(&Event{Type: EventCallStarted, CommitId: comm.Id})
+		var newComm *Commit
+		callCtx, cancel := comm.withTimeout(ctx)
+		release, err := own.limiter.acquire(callCtx, comm)
+		var attempts int
+		if err == nil {
+			attempts, err = comm.RetryPolicy.do(callCtx, comm.RateLimits, func(ctx context.Context) (err error) {
+				newComm, err = comm.Reviewer.(Preparer).CommitPrepared(ctx, comm)
+				return
+			})
+		}
+		release()
+		cancel()
+		comm.Attempts = attempts
+		comm.settle(err)
+		if err == nil {
+			comm.absorb(newComm)
+		}
+		res.finish(comm, newComm, err)
+		if err == nil {
+			own.recordApplied(ctx, comm, res)
+		}
+		own.emit(resultEvent(EventCallFinished, res))
+		own.report(res)
+		return err
+	})
+	own.abortUncommitted(ctx, pR, attempted)
+}
+
+// abortUncommitted aborts the prepared commits of the pull request whose commit wasn't attempted,
+// which were already recorded as failed. See Owner.mergeDAG
+func (own *Owner) abortUncommitted(ctx context.Context, pR *PullRequest, attempted []bool) {
+	forEach(own.deliveryWorkers(), len(pR.Commits), func(i int) {
+		comm := pR.Commits[i]
+		if attempted[i] || comm.Is(Merged) {
+			return
+		}
+		err := comm.Reviewer.(Preparer).Abort(detach(ctx), comm) // Even if the orchestration was canceled
+		if err != nil {
+			own.emit(&Event{Type: EventAbortFailed, CommitId: comm.Id, Error: err.Error()})
+		}
+	})
+}
+
+// abortTwoPhase aborts the prepared commits of the pull request, and records the
+// abortion of each of them. The prepareErrs are the errors of the preparation by commit index
+// Notice that if no preparation was performed, prepareErrs must be nil and none is aborted
+func (own *Owner) abortTwoPhase(ctx context.Context, pR *PullRequest, prepareErrs []error) {
+	forEach(own.deliveryWorkers(), len(pR.Commits), func(i int) {
+		comm := pR.Commits[i]
+		if comm.Is(Rejected) { // Already recorded by the review
+			return
+		}
+		if prepareErrs == nil {
+			_ = comm.transition(Failed)
+			own.report(&Result{CommitId: comm.Id, Error: errTwoPhaseAborted})
+			return
+		}
+		if err := prepareErrs[i]; err != nil {
+			_ = comm.transition(Failed)
+			own.report(resultOf(comm, err))
+			return
+		}
+		if comm.Is(Merged) { // Already applied by a previous orchestration
+			own.report(resultOf(comm, nil))
+			return
+		}
+		_ = comm.transition(Failed)
+		err := comm.Reviewer.(Preparer).Abort(detach(ctx), comm) // Even if the orchestration was canceled
+		own.report(&Result{CommitId: comm.Id, Error: xerrors.NewMultiErr(errTwoPhaseAborted, err)})
+	})
+}
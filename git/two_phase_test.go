@@ -0,0 +1,106 @@
+package git
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestOwner_mergeTwoPhase(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		reviewers     []Collaborator
+		wantTwoPhase  bool
+		wantCommitted int
+		wantAborted   int
+		wantQtResErrs int
+	}{
+		{
+			name:          "all the reviewers are PREPARED",
+			reviewers:     []Collaborator{&preparerMock{}, &preparerMock{}},
+			wantTwoPhase:  true,
+			wantCommitted: 2,
+		},
+		{
+			name:          "a reviewer FAILS its PREPARATION",
+			reviewers:     []Collaborator{&preparerMock{}, &preparerMock{PrepareErr: errFoo}},
+			wantTwoPhase:  true,
+			wantAborted:   1,
+			wantQtResErrs: 2,
+		},
+		{
+			name:      "NOT ALL the reviewers are PREPARERS",
+			reviewers: []Collaborator{&preparerMock{}, &collabMock{}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			own := &Owner{Waiter: &sync.WaitGroup{}}
+			own.Summary = make(chan *Result, len(tt.reviewers))
+			pR := &PullRequest{}
+			for _, reviewer := range tt.reviewers {
+				pR.Commits = append(pR.Commits, &Commit{
+					Changes:  []*Change{gChanges.Foo.Update.copy(t)},
+					Reviewer: reviewer,
+					State:    Delegated,
+				})
+			}
+			if got := pR.canTwoPhase(); got != tt.wantTwoPhase {
+				t.Errorf("PullRequest.canTwoPhase() = %v, want %v", got, tt.wantTwoPhase)
+			}
+			if !tt.wantTwoPhase {
+				return
+			}
+			own.mergeTwoPhase(context.Background(), pR)
+			close(own.Summary)
+
+			var gotCommitted, gotAborted int
+			for _, reviewer := range tt.reviewers {
+				gotCommitted += reviewer.(*preparerMock).Committed
+				gotAborted += reviewer.(*preparerMock).Aborted
+			}
+			if gotCommitted != tt.wantCommitted {
+				t.Errorf("Owner.mergeTwoPhase() committed = %v, want %v", gotCommitted, tt.wantCommitted)
+			}
+			if gotAborted != tt.wantAborted {
+				t.Errorf("Owner.mergeTwoPhase() aborted = %v, want %v", gotAborted, tt.wantAborted)
+			}
+			var gotQtResErrs int
+			for res := range own.Summary {
+				if res.Error != nil {
+					gotQtResErrs++
+				}
+			}
+			if gotQtResErrs != tt.wantQtResErrs {
+				t.Errorf("Owner.mergeTwoPhase() gotQtResErrs = %v, want %v", gotQtResErrs, tt.wantQtResErrs)
+			}
+		})
+	}
+}
+
+func TestOwner_mergeTwoPhase_failedDependency(t *testing.T) {
+	t.Parallel()
+	dep, dependent := &preparerMock{CommitErr: errFoo}, &preparerMock{}
+	own := &Owner{Waiter: &sync.WaitGroup{}, Summary: make(chan *Result, 2)}
+	pR := &PullRequest{Commits: []*Commit{
+		{Id: 1, Changes: []*Change{gChanges.Foo.Update.copy(t)}, Reviewer: dep, State: Delegated},
+		{Id: 2, Changes: []*Change{gChanges.Foo.Update.copy(t)}, Reviewer: dependent, State: Delegated,
+			DependsOn: []int64{1}},
+	}}
+	own.mergeTwoPhase(context.Background(), pR)
+	close(own.Summary)
+
+	if dependent.Prepared != 1 || dependent.Committed != 0 {
+		t.Fatalf("Owner.mergeTwoPhase() dependent prepared = %v, committed = %v; want 1, 0",
+			dependent.Prepared, dependent.Committed)
+	}
+	if dependent.Aborted != 1 {
+		t.Errorf("Owner.mergeTwoPhase() did NOT ABORT the prepared dependent of the failed commit")
+	}
+	if !pR.Commits[1].Is(Failed) {
+		t.Errorf("Owner.mergeTwoPhase() dependent is %v, want %v", pR.Commits[1].State, Failed)
+	}
+}
@@ -3,6 +3,7 @@ package git
 import (
 	"context"
 	"database/sql"
+	"sort"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
@@ -24,7 +25,7 @@ func Comm(
 	if err != nil {
 		return nil, errors.Wrap(err, "index fetch")
 	}
-	comms, err := branch.Index.Commit(ctx, db)
+	comms, err := branch.Index.Commit(ctx, db, branch.Id)
 	if err != nil {
 		return nil, errors.Wrap(err, "index commitment")
 	}
@@ -32,29 +33,44 @@ func Comm(
 }
 
 // Add wraps change adding from the inferred index
+// The change is validated against the schema the branch is bound to, which is the given schemaName when the
+// branch is created by it. See Branch.validate
 func Add(
 	ctx context.Context,
 	db *sqlx.DB,
+	project *schema.Planisphere,
 	entityId integrity.Id,
 	tableName integrity.TableName,
 	columnName integrity.ColumnName,
 	branchName integrity.BranchName,
+	schemaName integrity.SchemaName,
 	val interface{},
 	Type integrity.CRUD,
 	opts Options,
 ) (*Change, error) {
-	chg, err := NewChange(entityId, tableName, columnName, val, Type, opts) // The specific order is to avoid creating new branch with unvalid change
+	chg, err := NewChange(entityId, tableName, columnName, val, Type, opts)
 	if err != nil {
 		return nil, errors.Wrap(err, "new change")
 	}
 
 	branch, err := BranchByName(ctx, db, branchName)
-	if errors.Cause(err) == sql.ErrNoRows {
-		branch, err = NewBranchWithIndex(ctx, db, branchName)
+	isNew := errors.Cause(err) == sql.ErrNoRows
+	if isNew {
+		branch, err = &Branch{Name: string(branchName), Schema: schemaName}, nil
 	}
 	if err != nil {
 		return nil, errors.Wrap(err, "find branch by name")
 	}
+	err = branch.validate(project, schemaName, chg) // The specific order is to avoid creating new branch with unvalid change
+	if err != nil {
+		return nil, errors.Wrap(err, "validate change")
+	}
+	if isNew {
+		branch, err = newBranchWithIndex(ctx, db, branchName, schemaName)
+		if err != nil {
+			return nil, errors.Wrap(err, "new branch with index")
+		}
+	}
 
 	err = branch.FetchIndex(ctx, db)
 	if err != nil {
@@ -64,6 +80,12 @@ func Add(
 	if err != nil {
 		return nil, errors.Wrap(err, "fetch changes")
 	}
+	resolved, err := branch.TemporaryIds(ctx, db)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch temporary ids")
+	}
+	chg.resolveTemporaryIds(resolved)
+	chg.IndexId = branch.IndexId
 	err = branch.Index.Add(ctx, db, chg)
 	if err != nil {
 		return nil, errors.Wrap(err, "index add change")
@@ -109,6 +131,12 @@ func Rm(
 	return chg, nil
 }
 
+// Orchestrate merges the unmerged commits of the given branch through the community
+// It persists the resultant pull request, its results and the state of its commits, and resolves the
+// temporary ids of the created entities across the branch
+// The commits undone by the merged inverses become Reverted. See Revert
+// Notice the commits already applied by a previous orchestration are skipped. See Owner.Ledger
+// The schemaName can be empty, letting the commits span many schemas. See Owner.Delegate
 func Orchestrate(
 	ctx context.Context,
 	db *sqlx.DB,
@@ -116,20 +144,30 @@ func Orchestrate(
 	branchName integrity.BranchName,
 	schemaName integrity.SchemaName,
 	community *Community,
+	opts ...OwnerOption,
 ) (*PullRequest, error) {
 	own, err := NewOwner(project)
 	if err != nil {
 		return nil, err
 	}
+	own.CapturePreImages = true
+	own.Ledger = NewSQLLedger(db)
+	for _, opt := range opts {
+		opt(own)
+	}
 	branch, err := BranchByName(ctx, db, branchName)
 	if err != nil {
 		return nil, errors.Wrap(err, "find branch by name")
 	}
-	commits, err := branch.UnmergedCommits(ctx, db)
+	pR, err := unmergedPullRequest(ctx, db, branch)
 	if err != nil {
-		return nil, errors.Wrap(err, "branch fetch unmerged commits")
+		return nil, err
+	}
+	placeholders := pR.placeholders()
+	err = store.InsertIntoDB(ctx, db, pR) // Identifies the orchestration. See PullRequestTopic
+	if err != nil {
+		return nil, errors.Wrap(err, "insert pull request into db")
 	}
-	pR := NewPullRequest(commits)
 
 	own.Waiter.Add(1)
 	go own.Orchestrate(ctx, community, schemaName, pR)
@@ -137,19 +175,168 @@ func Orchestrate(
 	if err != nil {
 		return nil, errors.Wrap(err, "owner wait and close")
 	}
-	err = store.UpsertIntoDB(ctx, db, pR)
+	ctx = detach(ctx) // The outcome is persisted even if the orchestration was canceled meanwhile
+	var results []*Result
+	for res := range own.Summary {
+		results = append(results, res)
+	}
+	err = storeResults(ctx, db, pR, results)
 	if err != nil {
-		return nil, errors.Wrap(err, "upsert pull request into db")
+		return nil, errors.Wrap(err, "store results")
 	}
 
 	var comms []store.Storable
 	for _, comm := range pR.Commits {
+		err = storePreImage(ctx, db, comm)
+		if err != nil {
+			return nil, errors.Wrap(err, "store commit pre image")
+		}
+		err = storeCreatedEntityId(ctx, db, comm)
+		if err != nil {
+			return nil, errors.Wrap(err, "store created entity id")
+		}
 		comms = append(comms, comm)
 	}
 	err = store.UpsertIntoDB(ctx, db, comms...)
 	if err != nil {
 		return nil, errors.Wrap(err, "upsert commits into db")
 	}
+	err = storeTransitions(ctx, db, pR.Commits...)
+	if err != nil {
+		return nil, errors.Wrap(err, "store commits transitions")
+	}
+	err = storeReverts(ctx, db, pR.Commits...)
+	if err != nil {
+		return nil, errors.Wrap(err, "store reverts")
+	}
+	err = storeTemporaryIds(ctx, db, branch, placeholders)
+	if err != nil {
+		return nil, errors.Wrap(err, "store temporary ids")
+	}
 
 	return pR, nil
 }
+
+// PlanOrchestration describes what the orchestration of the unmerged commits of the given branch would perform
+// Notice nothing is delivered nor persisted. See Owner.Plan
+func PlanOrchestration(
+	ctx context.Context,
+	db *sqlx.DB,
+	project *schema.Planisphere,
+	branchName integrity.BranchName,
+	schemaName integrity.SchemaName,
+	community *Community,
+) (*Plan, error) {
+	own, err := NewOwner(project)
+	if err != nil {
+		return nil, err
+	}
+	own.Ledger = NewSQLLedger(db)
+	branch, err := BranchByName(ctx, db, branchName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find branch by name")
+	}
+	pR, err := unmergedPullRequest(ctx, db, branch)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := own.Plan(ctx, community, schemaName, pR)
+	if err != nil {
+		return nil, errors.Wrap(err, "owner plan")
+	}
+	return plan, nil
+}
+
+// unmergedPullRequest builds the pull request of the unmerged commits of the branch, with its
+// changes fetched and the already known temporary ids resolved
+func unmergedPullRequest(ctx context.Context, db *sqlx.DB, branch *Branch) (*PullRequest, error) {
+	commits, err := branch.UnmergedCommits(ctx, db)
+	if err != nil {
+		return nil, errors.Wrap(err, "branch fetch unmerged commits")
+	}
+	err = fetchCommitsChanges(ctx, db, commits)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch commits changes")
+	}
+	resolved, err := branch.TemporaryIds(ctx, db)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch temporary ids")
+	}
+	for _, comm := range commits {
+		for _, chg := range comm.Changes {
+			chg.resolveTemporaryIds(resolved)
+		}
+	}
+	return NewPullRequest(commits), nil
+}
+
+// Log retrieves the history of commits of the given branch which satisfies the filter
+func Log(
+	ctx context.Context,
+	db *sqlx.DB,
+	branchName integrity.BranchName,
+	filter *HistoryFilter,
+) (*History, error) {
+	branch, err := BranchByName(ctx, db, branchName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find branch by name")
+	}
+	hist, err := branch.History(ctx, db, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "branch history")
+	}
+	return hist, nil
+}
+
+// Revert stages onto the branch the commits which undo the given merged commits, in order to be orchestrated
+// The commits are reverted from the newest to the oldest one, and each of them becomes Reverted once its
+// inverse is merged. See Orchestrate
+// Notice the inverses are staged all together: if any of the commits can't be reverted, none is
+func Revert(
+	ctx context.Context,
+	db *sqlx.DB,
+	branchName integrity.BranchName,
+	commitIds ...int64,
+) ([]*Commit, error) {
+	branch, err := BranchByName(ctx, db, branchName)
+	if err != nil {
+		return nil, errors.Wrap(err, "find branch by name")
+	}
+
+	ids := append([]int64(nil), commitIds...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] > ids[j] })
+	var invs []*Commit
+	for _, id := range ids {
+		inv, err := branch.inverseOf(ctx, db, id)
+		if err != nil {
+			return nil, err
+		}
+		invs = append(invs, inv)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer tx.Rollback() // No-op once committed
+	for _, inv := range invs {
+		err = store.InsertIntoDB(ctx, tx, inv)
+		if err != nil {
+			return nil, errors.Wrap(err, "insert inverse commit into db")
+		}
+		var chgs []store.Storable
+		for _, chg := range inv.Changes {
+			chg.IndexId, chg.CommitId = branch.IndexId, inv.Id
+			chgs = append(chgs, chg)
+		}
+		err = store.InsertIntoDB(ctx, tx, chgs...)
+		if err != nil {
+			return nil, errors.Wrap(err, "insert inverse changes into db")
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "commit tx")
+	}
+	return invs, nil
+}
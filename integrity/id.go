@@ -10,3 +10,12 @@ func (id Id) IsNil() bool {
 	withoutZeros := strings.ReplaceAll(string(id), "0", "")
 	return withoutZeros == ""
 }
+
+// TemporaryIdPrefix is the prefix of the local placeholders of entities which weren't created yet
+const TemporaryIdPrefix = "tmp:"
+
+// IsTemporary verifies if the id is a local placeholder (e.g. tmp:xyz) of an entity to be created
+// Once the entity is created, the placeholder is resolved to the id returned by its collaborator
+func (id Id) IsTemporary() bool {
+	return strings.HasPrefix(string(id), TemporaryIdPrefix) && len(id) > len(TemporaryIdPrefix)
+}
@@ -21,3 +21,24 @@ func TestId_IsNil(t *testing.T) {
 		})
 	}
 }
+
+func TestId_IsTemporary(t *testing.T) {
+	tests := []struct {
+		name string
+		id   Id
+		want bool
+	}{
+		{name: "foo", id: "foo", want: false},
+		{name: "nil", id: "", want: false},
+		{name: "temporary", id: "tmp:foo", want: true},
+		{name: "only the prefix", id: "tmp:", want: false},
+		{name: "prefix in the middle", id: "foo:tmp:bar", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.IsTemporary(); got != tt.want {
+				t.Errorf("Id.IsTemporary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -29,7 +29,7 @@ func sqlColumnNames(storable Storable) string {
 	return name.Parenthize(strings.Join(storable.SQLColumns(), ","))
 }
 
-func UpsertIntoDB(ctx context.Context, db *sqlx.DB, storables ...Storable) error {
+func UpsertIntoDB(ctx context.Context, db sqlx.ExtContext, storables ...Storable) error {
 	var inserts, updates []Storable
 	for _, store := range storables {
 		if store.GetId() == 0 {
@@ -49,7 +49,7 @@ func UpsertIntoDB(ctx context.Context, db *sqlx.DB, storables ...Storable) error
 	return nil
 }
 
-func UpdateIntoDB(ctx context.Context, db *sqlx.DB, storables ...Storable) error {
+func UpdateIntoDB(ctx context.Context, db sqlx.ExtContext, storables ...Storable) error {
 	qtToStore := len(storables)
 	if qtToStore == 0 {
 		return nil
@@ -57,7 +57,7 @@ func UpdateIntoDB(ctx context.Context, db *sqlx.DB, storables ...Storable) error
 
 	ref := storables[0] // takes it as a reference for all entities given
 	qr := execBoilerplate("UPDATE", ref)
-	rows, err := db.NamedExecContext(ctx, qr, storables)
+	rows, err := sqlx.NamedExecContext(ctx, db, qr, storables)
 	if err != nil {
 		return errors.Wrap(err, "named exec ctx")
 	}
@@ -76,13 +76,13 @@ var errMismatchAffectedRows = errors.New("the affected rows quantity does not ma
 
 // InsertIntoDB inserts the storable entity to the DB
 // Finally, it assigns the inserted Id to the given entities
-func InsertIntoDB(ctx context.Context, db *sqlx.DB, storables ...Storable) error {
+func InsertIntoDB(ctx context.Context, db sqlx.ExtContext, storables ...Storable) error {
 	if len(storables) == 0 {
 		return errNilStorableEntity
 	}
 	ref := storables[0] // takes it as a reference for all entities given
 	qr := execBoilerplate("INSERT INTO", ref) + " RETURNING id"
-	ids, err := db.NamedQueryContext(ctx, qr, storables)
+	ids, err := sqlx.NamedQueryContext(ctx, db, qr, storables)
 	if err != nil {
 		return errors.Wrap(err, "named query ctx")
 	}
@@ -106,7 +106,7 @@ func InsertIntoDB(ctx context.Context, db *sqlx.DB, storables ...Storable) error
 	return nil
 }
 
-func DeleteFromDB(ctx context.Context, db *sqlx.DB, storable Storable) error {
+func DeleteFromDB(ctx context.Context, db sqlx.ExtContext, storable Storable) error {
 	if storable.GetId() == 0 {
 		return nil
 	}
@@ -114,8 +114,9 @@ func DeleteFromDB(ctx context.Context, db *sqlx.DB, storable Storable) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	_, err := db.NamedExecContext(
+	_, err := sqlx.NamedExecContext(
 		ctx,
+		db,
 		`DELETE FROM `+storable.SQLTable()+` WHERE id=:id`,
 		storable,
 	)
@@ -0,0 +1,11 @@
+package jobs
+
+import "errors"
+
+var (
+	errNilHandler    = errors.New("the HANDLER of the pool is NIL")
+	errLeaseExpired  = errors.New("the LEASE of the job EXPIRED too many times")
+	errLostLease     = errors.New("the job LEASE was LOST")
+	errNilBranchName = errors.New("the BRANCH NAME of the job is NIL")
+	errCommitsFailed = errors.New("ALL the COMMITS of the job FAILED or were REJECTED")
+)
@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/sebach1/rtc/integrity"
+)
+
+// A State describes the processing stage of a job
+type State string
+
+const (
+	// Queued jobs are waiting for a worker
+	Queued State = "queued"
+	// Running jobs are leased by a worker. Once its lease expires, they're queued again
+	Running State = "running"
+	// Succeeded jobs were entirely performed
+	Succeeded State = "succeeded"
+	// Failed jobs errored on its last attempt
+	Failed State = "failed"
+	// PartiallyFailed jobs were performed, but some of its commits failed or were rejected
+	PartiallyFailed State = "partially_failed"
+)
+
+// A Job is the durable request of an orchestration of the given branch
+type Job struct {
+	Id int64 `json:"id,omitempty"`
+
+	Branch integrity.BranchName `json:"branch,omitempty"`
+	Schema integrity.SchemaName `json:"schema,omitempty"` // Empty when its commits can span many schemas
+	Saga   bool                 `json:"saga,omitempty"`
+
+	State    State  `json:"state,omitempty"`
+	Attempts int    `json:"attempts,omitempty"`
+	Error    string `json:"error,omitempty"`
+
+	// LeasedUntil is the deadline of the worker which runs the job to send a heartbeat
+	// If it's exceeded, the job is considered abandoned and it's resumed by another worker
+	LeasedUntil *time.Time `json:"leased_until,omitempty"`
+
+	// PullRequestId is the pull request resultant of the orchestration
+	PullRequestId int64 `json:"pull_request_id,omitempty"`
+	// FailedCommitIds are the commits of the pull request which failed or were rejected
+	FailedCommitIds pq.Int64Array `json:"failed_commit_ids,omitempty"`
+
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Topic is the topic the progress of the given job is published onto. See git.Broadcaster
+func Topic(jobId int64) string {
+	return fmt.Sprintf("job:%d", jobId)
+}
+
+// NewJob returns a queued job which orchestrates the given branch through the team of the schema
+func NewJob(branchName integrity.BranchName, schName integrity.SchemaName) (*Job, error) {
+	if branchName == "" {
+		return nil, errNilBranchName
+	}
+	now := time.Now()
+	return &Job{Branch: branchName, Schema: schName, State: Queued, CreatedAt: now, UpdatedAt: now}, nil
+}
+
+// JobById retrieves the job with the given id
+func JobById(ctx context.Context, db *sqlx.DB, id int64) (*Job, error) {
+	job := &Job{}
+	err := db.GetContext(ctx, job, `SELECT * FROM jobs WHERE id=?`, id)
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
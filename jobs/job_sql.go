@@ -0,0 +1,40 @@
+package jobs
+
+// GetId wraps the id retrieval to implement Storable interface
+func (job *Job) GetId() int64 {
+	return job.Id
+}
+
+// SetId wraps the id assignation to implement Storable interface
+func (job *Job) SetId(id int64) {
+	job.Id = id
+}
+
+// SQLTable returns the sql SQLTable name of the entity
+//
+// Testing: tested by using naming conventions. See internal/name pkg
+func (job *Job) SQLTable() string {
+	return "jobs"
+}
+
+// SQLColumns returns the SQLColumns each field represent on db
+// Notice the returned slice is the list of struct tags of exported fields
+// It's done to avoid reflection
+//
+// Testing: tested by using reflection at Columns_Test to check being the tags
+func (job *Job) SQLColumns() []string {
+	return []string{
+		"id",
+		"branch",
+		"schema",
+		"saga",
+		"state",
+		"attempts",
+		"error",
+		"leased_until",
+		"pull_request_id",
+		"failed_commit_ids",
+		"created_at",
+		"updated_at",
+	}
+}
@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gedex/inflector"
+	"github.com/google/go-cmp/cmp"
+	"github.com/sebach1/rtc/internal/name"
+)
+
+func TestJobSQLColumns(t *testing.T) {
+	job := Job{}
+	typeOf := reflect.TypeOf(job)
+	var want []string
+	for i := 0; i < typeOf.NumField(); i++ {
+		col := name.ToSnakeCase(typeOf.Field(i).Name)
+		want = append(want, col)
+	}
+	sort.Strings(want)
+
+	got := job.SQLColumns()
+	sort.Strings(got)
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Job.SQLColumns() mismatch (-want +got): %s", diff)
+	}
+}
+
+func TestJobSQLTable(t *testing.T) {
+	job := Job{}
+	typeOf := reflect.TypeOf(job)
+	want := inflector.Pluralize(name.ToSnakeCase(typeOf.Name()))
+	got := job.SQLTable()
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Job.SQLTable() mismatch (-want +got): %s", diff)
+	}
+}
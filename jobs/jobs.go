@@ -0,0 +1,4 @@
+/*
+Package jobs provides a durable queue of orchestrations backed by the store, and the pool of workers which performs them
+*/
+package jobs
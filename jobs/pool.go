@@ -0,0 +1,171 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sebach1/rtc/git"
+	"github.com/sebach1/rtc/schema"
+)
+
+const (
+	defaultWorkers      = 4
+	defaultPollInterval = time.Second
+)
+
+// A Handler performs the work described by the job
+// It can assign the outcome of the job onto it (e.g. .PullRequestId)
+type Handler func(context.Context, *Job) error
+
+// Orchestrator returns the Handler which orchestrates the branch of the job through the given community
+// The job fails in case all the commits of the orchestration failed or were rejected. See Job.FailedCommitIds
+// If a broadcaster is given, the progress of the orchestration is published onto the topic of the job
+// See Topic
+func Orchestrator(
+	db *sqlx.DB,
+	project *schema.Planisphere,
+	community *git.Community,
+	broadcaster *git.Broadcaster,
+) Handler {
+	return func(ctx context.Context, job *Job) error {
+		var opts []git.OwnerOption
+		if job.Saga {
+			opts = append(opts, git.WithSaga())
+		}
+		if broadcaster != nil {
+			opts = append(opts, git.WithBroadcaster(broadcaster, Topic(job.Id)))
+		}
+		pR, err := git.Orchestrate(ctx, db, project, job.Branch, job.Schema, community, opts...)
+		if err != nil {
+			return err
+		}
+		job.PullRequestId = pR.Id
+		job.FailedCommitIds = nil
+		for _, comm := range pR.Commits {
+			if comm.Is(git.Failed, git.Rejected) {
+				job.FailedCommitIds = append(job.FailedCommitIds, comm.Id)
+			}
+		}
+		if len(pR.Commits) > 0 && len(job.FailedCommitIds) == len(pR.Commits) {
+			return errCommitsFailed
+		}
+		return nil
+	}
+}
+
+// A Pool is a group of workers which claims the jobs of the queue and performs them through the handler
+// While a job is running, its worker keeps its lease alive by sending heartbeats
+type Pool struct {
+	Queue   *Queue
+	Handler Handler
+
+	Workers      int
+	PollInterval time.Duration
+}
+
+// NewPool returns a pool with the default qt of workers and poll interval
+func NewPool(queue *Queue, handler Handler) *Pool {
+	return &Pool{Queue: queue, Handler: handler, Workers: defaultWorkers, PollInterval: defaultPollInterval}
+}
+
+// Run starts the workers and blocks until the ctx is done and all of them finished its current job
+// Notice the jobs left running by a previous process are resumed once its lease expires
+func (p *Pool) Run(ctx context.Context) error {
+	if p.Handler == nil {
+		return errNilHandler
+	}
+	workers := p.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (p *Pool) pollInterval() time.Duration {
+	if p.PollInterval <= 0 {
+		return defaultPollInterval
+	}
+	return p.PollInterval
+}
+
+// work claims and performs jobs until the ctx is done, waiting the poll interval when the queue is empty
+func (p *Pool) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		job, err := p.Queue.Claim(ctx)
+		if err != nil {
+			log.Printf("jobs: claim: %v", err)
+		}
+		if job == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.pollInterval()):
+			}
+			continue
+		}
+		p.process(ctx, job)
+	}
+}
+
+// process performs the job while its lease is kept alive, and records its outcome
+// If the lease is lost, the job is abandoned to the worker which resumed it
+func (p *Pool) process(ctx context.Context, job *Job) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		ticker := time.NewTicker(p.Queue.lease() / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-jobCtx.Done():
+				return
+			case <-ticker.C:
+				err := p.Queue.Heartbeat(jobCtx, job)
+				if err == errLostLease {
+					cancel()
+					return
+				}
+				if err != nil {
+					log.Printf("jobs: heartbeat of job %v: %v", job.Id, err)
+				}
+			}
+		}
+	}()
+
+	err := p.Handler(jobCtx, job)
+	lost := jobCtx.Err() != nil && ctx.Err() == nil
+	cancel()
+	<-heartbeatDone
+	if lost {
+		return
+	}
+
+	if err != nil {
+		err = p.Queue.Fail(context.Background(), job, err)
+	} else {
+		err = p.Queue.Complete(context.Background(), job)
+	}
+	if err != nil {
+		log.Printf("jobs: record outcome of job %v: %v", job.Id, err)
+	}
+}
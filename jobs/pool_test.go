@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sebach1/rtc/internal/test/thelper"
+)
+
+func TestPool_process(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   Handler
+		wantState State
+		wantPR    int64
+	}{
+		{
+			name: "SUCCEEDED handler completes the job",
+			handler: func(ctx context.Context, job *Job) error {
+				job.PullRequestId = 5
+				return nil
+			},
+			wantState: Succeeded,
+			wantPR:    5,
+		},
+		{
+			name:      "ERRORED handler fails the job",
+			handler:   func(ctx context.Context, job *Job) error { return errFoo },
+			wantState: Queued,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := thelper.MockDB(t)
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE jobs SET state=?, error=?, leased_until=?, pull_request_id=?")).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			job := &Job{Id: 1, State: Running, Attempts: 1}
+			pool := NewPool(NewQueue(db), tt.handler)
+			pool.process(context.Background(), job)
+			if job.State != tt.wantState {
+				t.Errorf("Pool.process() state = %v, want %v", job.State, tt.wantState)
+			}
+			if job.PullRequestId != tt.wantPR {
+				t.Errorf("Pool.process() pull request id = %v, want %v", job.PullRequestId, tt.wantPR)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Pool.process() unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPool_Run(t *testing.T) {
+	if err := (&Pool{}).Run(context.Background()); err != errNilHandler {
+		t.Errorf("Pool.Run() error = %v, wantErr %v", err, errNilHandler)
+	}
+}
@@ -0,0 +1,158 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/internal/store"
+)
+
+const (
+	defaultLease       = 30 * time.Second
+	defaultMaxAttempts = 3
+)
+
+// A Queue is the durable queue of jobs, backed by the jobs table
+// Jobs are claimed with row locking (FOR UPDATE SKIP LOCKED), so many workers, even from
+// different processes, can consume the same queue without taking the same job
+type Queue struct {
+	DB *sqlx.DB
+
+	// Lease is the time a claimed job remains owned by its worker without a heartbeat
+	Lease time.Duration
+
+	// MaxAttempts is the qt of times a job is claimed before it's definitively failed
+	MaxAttempts int
+}
+
+// NewQueue returns a queue over the given db with the default lease and attempts
+func NewQueue(db *sqlx.DB) *Queue {
+	return &Queue{DB: db, Lease: defaultLease, MaxAttempts: defaultMaxAttempts}
+}
+
+func (q *Queue) lease() time.Duration {
+	if q.Lease <= 0 {
+		return defaultLease
+	}
+	return q.Lease
+}
+
+func (q *Queue) maxAttempts() int {
+	if q.MaxAttempts <= 0 {
+		return defaultMaxAttempts
+	}
+	return q.MaxAttempts
+}
+
+// Enqueue persists the given job in order to be claimed by any worker
+func (q *Queue) Enqueue(ctx context.Context, job *Job) error {
+	job.State = Queued
+	return store.InsertIntoDB(ctx, q.DB, job)
+}
+
+// Claim leases the oldest available job, which is a queued one or a running one whose lease
+// expired (e.g. its worker crashed). It returns a nil job when there are none available
+// Notice the abandoned jobs which already reached the max attempts are failed instead of claimed
+func (q *Queue) Claim(ctx context.Context) (*Job, error) {
+	now := time.Now()
+	_, err := q.DB.ExecContext(ctx,
+		`UPDATE jobs SET state=?, error=?, updated_at=? WHERE state=? AND leased_until<? AND attempts>=?`,
+		Failed, errLeaseExpired.Error(), now, Running, now, q.maxAttempts(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail expired jobs")
+	}
+
+	tx, err := q.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer tx.Rollback() // No-op once committed
+
+	job := &Job{}
+	err = tx.GetContext(ctx, job,
+		`SELECT * FROM jobs WHERE state=? OR (state=? AND leased_until<?) ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED`,
+		Queued, Running, now,
+	)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "select available job")
+	}
+
+	leasedUntil := now.Add(q.lease())
+	job.State = Running
+	job.Attempts++
+	job.LeasedUntil = &leasedUntil
+	job.UpdatedAt = now
+	_, err = tx.ExecContext(ctx,
+		`UPDATE jobs SET state=?, attempts=?, leased_until=?, updated_at=? WHERE id=?`,
+		job.State, job.Attempts, job.LeasedUntil, job.UpdatedAt, job.Id,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "lease job")
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.Wrap(err, "commit tx")
+	}
+	return job, nil
+}
+
+// Heartbeat extends the lease of the running job
+// It returns an error if the job was no longer leased by the worker (e.g. it was resumed by another one)
+func (q *Queue) Heartbeat(ctx context.Context, job *Job) error {
+	now := time.Now()
+	leasedUntil := now.Add(q.lease())
+	res, err := q.DB.ExecContext(ctx,
+		`UPDATE jobs SET leased_until=?, updated_at=? WHERE id=? AND state=? AND attempts=?`,
+		leasedUntil, now, job.Id, Running, job.Attempts,
+	)
+	if err != nil {
+		return err
+	}
+	qt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if qt == 0 {
+		return errLostLease
+	}
+	job.LeasedUntil = &leasedUntil
+	return nil
+}
+
+// Complete marks the running job as succeeded, or as partially failed in case some of its commits
+// failed or were rejected. See Job.FailedCommitIds
+func (q *Queue) Complete(ctx context.Context, job *Job) error {
+	job.State = Succeeded
+	if len(job.FailedCommitIds) > 0 {
+		job.State = PartiallyFailed
+	}
+	job.Error = ""
+	return q.finish(ctx, job)
+}
+
+// Fail records the error of the running job. The job is queued again unless it reached the max attempts
+func (q *Queue) Fail(ctx context.Context, job *Job, jobErr error) error {
+	job.State = Queued
+	if job.Attempts >= q.maxAttempts() {
+		job.State = Failed
+	}
+	job.Error = jobErr.Error()
+	return q.finish(ctx, job)
+}
+
+func (q *Queue) finish(ctx context.Context, job *Job) error {
+	job.LeasedUntil = nil
+	job.UpdatedAt = time.Now()
+	_, err := q.DB.ExecContext(ctx,
+		`UPDATE jobs SET state=?, error=?, leased_until=?, pull_request_id=?, failed_commit_ids=?, updated_at=? WHERE id=? AND attempts=?`,
+		job.State, job.Error, job.LeasedUntil, job.PullRequestId, job.FailedCommitIds, job.UpdatedAt, job.Id, job.Attempts,
+	)
+	return err
+}
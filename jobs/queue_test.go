@@ -0,0 +1,163 @@
+package jobs
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/sebach1/rtc/internal/test/thelper"
+)
+
+var errFoo = errors.New("foo")
+
+var jobCols = []string{"id", "branch", "schema", "state", "attempts"}
+
+func TestQueue_Claim(t *testing.T) {
+	tests := []struct {
+		name    string
+		stub    func(mock sqlmock.Sqlmock)
+		want    *Job
+		wantErr error
+	}{
+		{
+			name: "EMPTY queue",
+			stub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE jobs SET state=?, error=?")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("FOR UPDATE SKIP LOCKED")).WillReturnRows(sqlmock.NewRows(jobCols))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "LEASES the available job",
+			stub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE jobs SET state=?, error=?")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("FOR UPDATE SKIP LOCKED")).
+					WillReturnRows(sqlmock.NewRows(jobCols).AddRow(1, "foo", "bar", Queued, 0))
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE jobs SET state=?, attempts=?, leased_until=?")).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			want: &Job{Id: 1, Branch: "foo", Schema: "bar", State: Running, Attempts: 1},
+		},
+		{
+			name: "SELECT returns ERR",
+			stub: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(regexp.QuoteMeta("UPDATE jobs SET state=?, error=?")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta("FOR UPDATE SKIP LOCKED")).WillReturnError(errFoo)
+				mock.ExpectRollback()
+			},
+			wantErr: errFoo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := thelper.MockDB(t)
+			tt.stub(mock)
+			got, err := NewQueue(db).Claim(context.Background())
+			if errors.Cause(err) != tt.wantErr {
+				t.Errorf("Queue.Claim() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != nil {
+				if got.LeasedUntil == nil {
+					t.Errorf("Queue.Claim() claimed job WITHOUT LEASE")
+				}
+				got.LeasedUntil, got.UpdatedAt = nil, tt.want.UpdatedAt
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Queue.Claim() mismatch (-want +got): %s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Queue.Claim() unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestQueue_Heartbeat(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "EXTENDS the lease", affected: 1},
+		{name: "LOST lease", affected: 0, wantErr: errLostLease},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := thelper.MockDB(t)
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE jobs SET leased_until=?")).WillReturnResult(sqlmock.NewResult(0, tt.affected))
+			err := NewQueue(db).Heartbeat(context.Background(), &Job{Id: 1, State: Running, Attempts: 1})
+			if err != tt.wantErr {
+				t.Errorf("Queue.Heartbeat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestQueue_Fail(t *testing.T) {
+	tests := []struct {
+		name      string
+		job       *Job
+		wantState State
+	}{
+		{name: "job WITH attempts left is QUEUED again", job: &Job{Id: 1, State: Running, Attempts: 1}, wantState: Queued},
+		{name: "job WITHOUT attempts left is FAILED", job: &Job{Id: 1, State: Running, Attempts: 3}, wantState: Failed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := thelper.MockDB(t)
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE jobs SET state=?, error=?, leased_until=?")).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			err := NewQueue(db).Fail(context.Background(), tt.job, errFoo)
+			if err != nil {
+				t.Fatalf("Queue.Fail() error = %v", err)
+			}
+			if tt.job.State != tt.wantState {
+				t.Errorf("Queue.Fail() state = %v, want %v", tt.job.State, tt.wantState)
+			}
+			if tt.job.Error != errFoo.Error() || tt.job.LeasedUntil != nil {
+				t.Errorf("Queue.Fail() error = %q and lease = %v, want %q and no lease", tt.job.Error, tt.job.LeasedUntil, errFoo)
+			}
+		})
+	}
+}
+
+func TestQueue_Complete(t *testing.T) {
+	tests := []struct {
+		name      string
+		job       *Job
+		wantState State
+	}{
+		{name: "job WITHOUT failed commits SUCCEEDS", job: &Job{Id: 1, State: Running, Attempts: 1}, wantState: Succeeded},
+		{
+			name:      "job WITH failed commits PARTIALLY FAILS",
+			job:       &Job{Id: 1, State: Running, Attempts: 1, FailedCommitIds: []int64{2}},
+			wantState: PartiallyFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := thelper.MockDB(t)
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE jobs SET state=?, error=?, leased_until=?")).
+				WithArgs(tt.wantState, "", nil, 0, tt.job.FailedCommitIds, sqlmock.AnyArg(), tt.job.Id, tt.job.Attempts).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			err := NewQueue(db).Complete(context.Background(), tt.job)
+			if err != nil {
+				t.Fatalf("Queue.Complete() error = %v", err)
+			}
+			if tt.job.State != tt.wantState {
+				t.Errorf("Queue.Complete() state = %v, want %v", tt.job.State, tt.wantState)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Queue.Complete() unmet expectations: %v", err)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package github
 
 import (
+	"time"
+
 	"github.com/sebach1/rtc/git"
 )
 
@@ -14,5 +16,15 @@ var OpenSource = &git.Community{
 			{AssignedTable: "repositories", Collab: new(repositories)},
 			{AssignedTable: "organizations", Collab: new(organizations)},
 		},
+		RetryPolicy: &git.RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: 200 * time.Millisecond,
+			MaxBackoff:     2 * time.Second,
+			Multiplier:     2,
+			Jitter:         0.2,
+			AttemptTimeout: 10 * time.Second,
+		},
+		// The secondary rate limits of the content creation of GitHub allow about 80 calls per minute
+		RateLimit: &git.RateLimit{Rate: 80.0 / 60, Burst: 10},
 	},
 }
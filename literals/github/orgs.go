@@ -19,7 +19,40 @@ func (orgs *organizations) URL(owner string) string {
 	return fmt.Sprintf("%v/orgs/%v", baseURL, owner)
 }
 
+// Render describes the request the commit would be delivered with
+func (orgs *organizations) Render(ctx context.Context, comm *git.Commit) (*git.Request, error) {
+	req, err := orgs.request(ctx, comm)
+	if err != nil {
+		return nil, err
+	}
+	return literals.Render(req)
+}
+
 func (orgs *organizations) Create(ctx context.Context, comm *git.Commit) (*git.Commit, error) {
+	req, err := orgs.request(ctx, comm)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+	literals.ObserveRateLimit(res)
+	err = literals.CheckStatus(res)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := git.CommitFromCloser(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	return commit, nil
+}
+
+func (orgs *organizations) request(ctx context.Context, comm *git.Commit) (*http.Request, error) {
 	commType, _ := comm.Type()
 
 	body, err := msh.ToJSON(comm)
@@ -41,16 +74,6 @@ func (orgs *organizations) Create(ctx context.Context, comm *git.Commit) (*git.C
 	if err != nil {
 		return nil, err
 	}
-
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	defer res.Body.Close()
-	commit, err := git.CommitFromCloser(res.Body)
-	if err != nil {
-		return nil, err
-	}
-	return commit, nil
+	literals.SetIdempotencyKey(req)
+	return req, nil
 }
@@ -0,0 +1,18 @@
+package literals
+
+import (
+	"net/http"
+
+	"github.com/sebach1/rtc/git"
+)
+
+// IdempotencyKeyHeader is the header which carries the idempotency key of the delivered commit
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// SetIdempotencyKey sets the idempotency key carried by the context of the request as its header,
+// letting the services discard the repeated calls. See git.Commit.IdempotencyKey
+func SetIdempotencyKey(req *http.Request) {
+	if key, ok := git.IdempotencyKeyFrom(req.Context()); ok {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+}
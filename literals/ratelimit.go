@@ -0,0 +1,74 @@
+package literals
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sebach1/rtc/git"
+)
+
+// The headers by which the services tell its rate limits
+const (
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+	RetryAfterHeader         = "Retry-After"
+)
+
+// ObserveRateLimit adapts the rate limits the request was throttled by (see git.RateLimitsFrom) to the
+// ones the service tells by the headers of the response
+// The X-RateLimit-Reset header is expected as unix seconds, and the Retry-After one either as
+// seconds or as an HTTP date
+func ObserveRateLimit(res *http.Response) {
+	if res.Request == nil {
+		return
+	}
+	limits := git.RateLimitsFrom(res.Request.Context())
+	if len(limits) == 0 {
+		return
+	}
+	if retryAfter, ok := parseRetryAfter(res.Header); ok {
+		for _, rl := range limits {
+			rl.Pause(retryAfter)
+		}
+	}
+	remaining, err := strconv.Atoi(res.Header.Get(RateLimitRemainingHeader))
+	if err != nil {
+		return
+	}
+	reset := time.Now().Add(time.Minute) // As most of the limits are per minute
+	if unix, err := strconv.ParseInt(res.Header.Get(RateLimitResetHeader), 10, 64); err == nil {
+		reset = time.Unix(unix, 0)
+	}
+	for _, rl := range limits {
+		rl.Adapt(remaining, reset)
+	}
+}
+
+// parseRetryAfter retrieves the wait asked by the Retry-After header, if it's given
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get(RetryAfterHeader)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+// parseRateLimitExhausted retrieves the time the rate limit resets, in case the X-RateLimit-* headers
+// tell it was exhausted
+func parseRateLimitExhausted(header http.Header) (time.Time, bool) {
+	if header.Get(RateLimitRemainingHeader) != "0" {
+		return time.Time{}, false
+	}
+	unix, err := strconv.ParseInt(header.Get(RateLimitResetHeader), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(unix, 0), true
+}
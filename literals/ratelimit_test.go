@@ -0,0 +1,109 @@
+package literals
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func Test_parseRetryAfter(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{
+			name: "NOT GIVEN",
+		},
+		{
+			name:   "as SECONDS",
+			value:  "120",
+			want:   120 * time.Second,
+			wantOk: true,
+		},
+		{
+			name:   "as HTTP DATE",
+			value:  time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+			want:   time.Hour,
+			wantOk: true,
+		},
+		{
+			name:  "MALFORMED",
+			value: "soon",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			header := http.Header{}
+			if tt.value != "" {
+				header.Set(RetryAfterHeader, tt.value)
+			}
+			got, ok := parseRetryAfter(header)
+			if ok != tt.wantOk {
+				t.Errorf("parseRetryAfter() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if diff := got - tt.want; diff < -2*time.Second || diff > 2*time.Second { // HTTP dates lack sub-seconds
+				t.Errorf("parseRetryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseRateLimitExhausted(t *testing.T) {
+	t.Parallel()
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	unix := strconv.FormatInt(reset.Unix(), 10)
+	tests := []struct {
+		name      string
+		remaining string
+		reset     string
+		want      time.Time
+		wantOk    bool
+	}{
+		{
+			name:      "EXHAUSTED with RESET",
+			remaining: "0",
+			reset:     unix,
+			want:      reset,
+			wantOk:    true,
+		},
+		{
+			name:      "EXHAUSTED without RESET",
+			remaining: "0",
+		},
+		{
+			name:      "NOT EXHAUSTED",
+			remaining: "10",
+			reset:     unix,
+		},
+		{
+			name:  "NOT GIVEN",
+			reset: unix,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			header := http.Header{}
+			if tt.remaining != "" {
+				header.Set(RateLimitRemainingHeader, tt.remaining)
+			}
+			if tt.reset != "" {
+				header.Set(RateLimitResetHeader, tt.reset)
+			}
+			got, ok := parseRateLimitExhausted(header)
+			if ok != tt.wantOk {
+				t.Errorf("parseRateLimitExhausted() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseRateLimitExhausted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package literals
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/sebach1/rtc/git"
+)
+
+// Render describes the given request without performing it, in order to let the HTTP literals
+// be Renderers. See git.Owner.Plan
+func Render(req *http.Request) (*git.Request, error) {
+	rendered := &git.Request{Method: req.Method, URL: req.URL.String(), Header: req.Header}
+	if req.GetBody == nil {
+		return rendered, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	rendered.Body, err = ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
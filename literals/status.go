@@ -0,0 +1,59 @@
+package literals
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// A StatusError is the error of a response whose status code isn't successful
+// It implements git.RetryableError, in order to let the retry policies discard the permanent failures,
+// and git.ThrottledError, in order to let the throttled calls wait instead of failing
+type StatusError struct {
+	StatusCode int
+	Status     string
+
+	// RetryAfter is the wait asked by the service through the Retry-After header, if any
+	RetryAfter time.Duration
+	// RateLimitReset is the time the rate limit of the service resets, in case the response tells it
+	// was exhausted through the X-RateLimit-* headers (e.g. the primary rate limit of GitHub)
+	RateLimitReset time.Time
+}
+
+func (err *StatusError) Error() string {
+	return fmt.Sprintf("unsuccessful response status: %v", err.Status)
+}
+
+// Retryable tells if the failure is transient, which is the case of the server errors
+// (e.g. 502 Bad Gateway) and the rate limitations
+func (err *StatusError) Retryable() bool {
+	return err.StatusCode >= http.StatusInternalServerError || err.StatusCode == http.StatusTooManyRequests ||
+		err.rateLimited()
+}
+
+// rateLimited tells if the call was forbidden as the rate limit of the service was exhausted
+func (err *StatusError) rateLimited() bool {
+	return err.StatusCode == http.StatusForbidden && !err.RateLimitReset.IsZero()
+}
+
+// Throttled tells if the call was rejected by the rate limits of the service, which is the case of
+// the 429 Too Many Requests responses, the 503 Service Unavailable ones which ask for a wait, and
+// the 403 Forbidden ones which tell the rate limit was exhausted, whose wait lasts until it resets
+func (err *StatusError) Throttled() (time.Duration, bool) {
+	if err.rateLimited() && err.RetryAfter <= 0 {
+		return time.Until(err.RateLimitReset), true
+	}
+	throttled := err.StatusCode == http.StatusTooManyRequests || err.rateLimited() ||
+		(err.StatusCode == http.StatusServiceUnavailable && err.RetryAfter > 0)
+	return err.RetryAfter, throttled
+}
+
+// CheckStatus returns a *StatusError if the status code of the response isn't successful
+func CheckStatus(res *http.Response) error {
+	if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+	retryAfter, _ := parseRetryAfter(res.Header)
+	reset, _ := parseRateLimitExhausted(res.Header)
+	return &StatusError{StatusCode: res.StatusCode, Status: res.Status, RetryAfter: retryAfter, RateLimitReset: reset}
+}
@@ -0,0 +1,144 @@
+package literals
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCheckStatus(t *testing.T) {
+	t.Parallel()
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	tests := []struct {
+		name          string
+		statusCode    int
+		header        map[string]string
+		wantErr       bool
+		wantRetryable bool
+		wantThrottled bool
+		wantWait      time.Duration
+	}{
+		{
+			name:       "SUCCESSFUL",
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:       "403 with EXHAUSTED rate limit waits until it resets",
+			statusCode: http.StatusForbidden,
+			header: map[string]string{
+				RateLimitRemainingHeader: "0",
+				RateLimitResetHeader:     reset,
+			},
+			wantErr:       true,
+			wantRetryable: true,
+			wantThrottled: true,
+			wantWait:      time.Hour,
+		},
+		{
+			name:       "403 with EXHAUSTED rate limit and RETRY-AFTER waits the asked time",
+			statusCode: http.StatusForbidden,
+			header: map[string]string{
+				RateLimitRemainingHeader: "0",
+				RateLimitResetHeader:     reset,
+				RetryAfterHeader:         "60",
+			},
+			wantErr:       true,
+			wantRetryable: true,
+			wantThrottled: true,
+			wantWait:      time.Minute,
+		},
+		{
+			name:       "403 with REMAINING rate limit is permanent",
+			statusCode: http.StatusForbidden,
+			header: map[string]string{
+				RateLimitRemainingHeader: "10",
+				RateLimitResetHeader:     reset,
+			},
+			wantErr: true,
+		},
+		{
+			name:       "403 without rate limit is permanent",
+			statusCode: http.StatusForbidden,
+			wantErr:    true,
+		},
+		{
+			name:          "429 with RETRY-AFTER",
+			statusCode:    http.StatusTooManyRequests,
+			header:        map[string]string{RetryAfterHeader: "30"},
+			wantErr:       true,
+			wantRetryable: true,
+			wantThrottled: true,
+			wantWait:      30 * time.Second,
+		},
+		{
+			name:          "429 without RETRY-AFTER",
+			statusCode:    http.StatusTooManyRequests,
+			wantErr:       true,
+			wantRetryable: true,
+			wantThrottled: true,
+		},
+		{
+			name:          "429 with MALFORMED RETRY-AFTER",
+			statusCode:    http.StatusTooManyRequests,
+			header:        map[string]string{RetryAfterHeader: "soon"},
+			wantErr:       true,
+			wantRetryable: true,
+			wantThrottled: true,
+		},
+		{
+			name:          "5xx is transient",
+			statusCode:    http.StatusBadGateway,
+			wantErr:       true,
+			wantRetryable: true,
+		},
+		{
+			name:          "503 with RETRY-AFTER is throttled",
+			statusCode:    http.StatusServiceUnavailable,
+			header:        map[string]string{RetryAfterHeader: "10"},
+			wantErr:       true,
+			wantRetryable: true,
+			wantThrottled: true,
+			wantWait:      10 * time.Second,
+		},
+		{
+			name:       "4xx is permanent",
+			statusCode: http.StatusNotFound,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			res := &http.Response{StatusCode: tt.statusCode, Status: http.StatusText(tt.statusCode), Header: http.Header{}}
+			for key, value := range tt.header {
+				res.Header.Set(key, value)
+			}
+			err := CheckStatus(res)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			statusErr, ok := err.(*StatusError)
+			if !ok {
+				t.Fatalf("CheckStatus() error = %T, want *StatusError", err)
+			}
+			if statusErr.StatusCode != tt.statusCode {
+				t.Errorf("StatusError.StatusCode = %v, want %v", statusErr.StatusCode, tt.statusCode)
+			}
+			if got := statusErr.Retryable(); got != tt.wantRetryable {
+				t.Errorf("StatusError.Retryable() = %v, want %v", got, tt.wantRetryable)
+			}
+			wait, throttled := statusErr.Throttled()
+			if throttled != tt.wantThrottled {
+				t.Errorf("StatusError.Throttled() throttled = %v, want %v", throttled, tt.wantThrottled)
+			}
+			if diff := wait - tt.wantWait; diff < -2*time.Second || diff > 2*time.Second { // Resets lack sub-seconds
+				t.Errorf("StatusError.Throttled() wait = %v, want %v", wait, tt.wantWait)
+			}
+		})
+	}
+}
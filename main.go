@@ -1,23 +1,58 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 
+	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/sebach1/rtc/cli"
+	"github.com/sebach1/rtc/config"
+	"github.com/sebach1/rtc/git"
+	"github.com/sebach1/rtc/internal/name"
+	"github.com/sebach1/rtc/jobs"
+	"github.com/sebach1/rtc/literals/github"
+	"github.com/sebach1/rtc/schema"
 	"github.com/sebach1/rtc/server"
 
 	"github.com/valyala/fasthttp"
 )
 
 func main() {
+	db, err := sqlx.Open("postgres", config.DataSource())
+	if err != nil {
+		log.Fatal(err)
+	}
+	db.MapperFunc(name.ToSnakeCase)
+	project := &schema.Planisphere{github.GitHub}
+
+	if len(os.Args) > 1 { // e.g. rtc plan -branch foo -schema github
+		env := &cli.Env{DB: db, Project: project, Community: github.OpenSource, Out: os.Stdout}
+		err = cli.Run(context.Background(), env, os.Args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	Port := os.Getenv("PORT")
 	if Port == "" {
 		Port = "8888"
 	}
 	Port = fmt.Sprintf(":%s", Port)
 
+	broadcaster := git.NewBroadcaster()
+	server.Project, server.Community, server.Broadcaster = project, github.OpenSource, broadcaster
+	pool := jobs.NewPool(jobs.NewQueue(db), jobs.Orchestrator(db, project, github.OpenSource, broadcaster))
+	go func() {
+		err := pool.Run(context.Background())
+		if err != nil {
+			log.Fatal(err)
+		}
+	}()
+
 	log.Println(fmt.Sprintf("Accepting connections at: %s", Port))
 	log.Fatal(fasthttp.ListenAndServe(Port, server.Router))
 }
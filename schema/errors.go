@@ -18,6 +18,7 @@ var (
 	errNilTableName     = errors.New("the TABLE NAME is NIL")
 	errNilColumns       = errors.New("the COLUMNS cannot be NIL")
 	errNilTable         = errors.New("the TABLE is NIL")
+	errAmbiguousTable   = errors.New("the TABLE given BELONGS to MANY SCHEMAS")
 
 	// Column errs
 	errNonexistentColumn   = errors.New("the COLUMN given does NOT EXISTS")
@@ -32,3 +32,42 @@ func (psph Planisphere) preciseTableErr(tableName integrity.TableName) (err erro
 	}
 	return errNonexistentTable
 }
+
+// TableByName retrieves the first table with the given name along all the schemas of the planisphere
+func (psph Planisphere) TableByName(tableName integrity.TableName) (*Table, error) {
+	for _, sch := range psph {
+		if sch == nil {
+			continue
+		}
+		for _, table := range sch.Blueprint {
+			if table != nil && table.Name == tableName {
+				return table, nil
+			}
+		}
+	}
+	return nil, errNonexistentTable
+}
+
+// SchemaOfTable retrieves the schema which contains the table with the given name
+// It errs if no schema, or more than one of them, contains the table
+func (psph Planisphere) SchemaOfTable(tableName integrity.TableName) (*Schema, error) {
+	var found *Schema
+	for _, sch := range psph {
+		if sch == nil {
+			continue
+		}
+		for _, table := range sch.Blueprint {
+			if table == nil || table.Name != tableName {
+				continue
+			}
+			if found != nil && found != sch {
+				return nil, errAmbiguousTable
+			}
+			found = sch
+		}
+	}
+	if found == nil {
+		return nil, errNonexistentTable
+	}
+	return found, nil
+}
@@ -105,3 +105,93 @@ func TestPlanisphere_GetSchemaFromName(t *testing.T) {
 		})
 	}
 }
+
+func TestPlanisphere_TableByName(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		tableName integrity.TableName
+	}
+
+	tests := []struct {
+		name    string
+		psph    Planisphere
+		args    args
+		want    *Table
+		wantErr error
+	}{
+		{
+			name: "given tableName is in a schema",
+			args: args{gTables.Foo.Name},
+			psph: Planisphere{gSchemas.Bar, gSchemas.Foo},
+			want: gTables.Foo,
+		},
+		{
+			name:    "given tableName doesn't exists on any scoped schema",
+			args:    args{gTables.Foo.Name},
+			psph:    Planisphere{nil, gSchemas.Bar},
+			wantErr: errNonexistentTable,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.psph.TableByName(tt.args.tableName)
+			if err != tt.wantErr {
+				t.Errorf("Planisphere.TableByName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Planisphere.TableByName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanisphere_SchemaOfTable(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		tableName integrity.TableName
+	}
+
+	tests := []struct {
+		name    string
+		psph    Planisphere
+		args    args
+		want    *Schema
+		wantErr error
+	}{
+		{
+			name: "given tableName is in a single schema",
+			args: args{gTables.Foo.Name},
+			psph: Planisphere{nil, gSchemas.Bar, gSchemas.Foo},
+			want: gSchemas.Foo,
+		},
+		{
+			name:    "given tableName is in MANY schemas",
+			args:    args{gTables.Foo.Name},
+			psph:    Planisphere{gSchemas.Foo, gSchemas.FooBar},
+			wantErr: errAmbiguousTable,
+		},
+		{
+			name:    "given tableName doesn't exists on any scoped schema",
+			args:    args{gTables.Foo.Name},
+			psph:    Planisphere{nil, gSchemas.Bar},
+			wantErr: errNonexistentTable,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.psph.SchemaOfTable(tt.args.tableName)
+			if err != tt.wantErr {
+				t.Errorf("Planisphere.SchemaOfTable() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Planisphere.SchemaOfTable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
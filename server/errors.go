@@ -3,7 +3,15 @@ package server
 import "errors"
 
 var (
-	errNoTable  = errors.New("TABLE is NOT GIVEN in the request body")
-	errNoBranch = errors.New("BRANCH is NOT GIVEN in the request body")
-	errNoColumn = errors.New("COLUMN is NOT GIVEN in the request body")
+	errNoTable         = errors.New("TABLE is NOT GIVEN in the request body")
+	errNoBranch        = errors.New("BRANCH is NOT GIVEN in the request body")
+	errNoColumn        = errors.New("COLUMN is NOT GIVEN in the request body")
+	errNoCommits       = errors.New("COMMITS are NOT GIVEN in the request body")
+	errNoTarget        = errors.New("the TARGET BRANCH (into) is NOT GIVEN in the request body")
+	errNoOnto          = errors.New("the BRANCH to REBASE ONTO is NOT GIVEN in the request body")
+	errNoStash         = errors.New("STASH is NOT GIVEN in the request body")
+	errNoJob           = errors.New("JOB is NOT GIVEN in the request body")
+	errNoPullRequest   = errors.New("PULL REQUEST is NOT GIVEN in the request body")
+	errNoOrchestration = errors.New("neither PULL REQUEST nor JOB are GIVEN in the query")
+	errNoBroadcaster   = errors.New("the server has NO BROADCASTER of events")
 )
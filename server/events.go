@@ -0,0 +1,93 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sebach1/rtc/git"
+	"github.com/sebach1/rtc/jobs"
+	"github.com/valyala/fasthttp"
+)
+
+// keepAliveInterval is the interval of the comments sent to the idle streams, which
+// lets the server notice the clients gone
+const keepAliveInterval = 15 * time.Second
+
+// eventsHandler streams the progress of an orchestration as Server-Sent Events
+// The orchestration is given by the pull_request or job query args, and the stream ends
+// once the orchestration finishes
+func eventsHandler(reqCtx *fasthttp.RequestCtx) {
+	topic, err := eventsTopic(reqCtx.QueryArgs())
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusUnprocessableEntity)
+		return
+	}
+	if Broadcaster == nil {
+		reqCtx.Error(errNoBroadcaster.Error(), fasthttp.StatusServiceUnavailable)
+		return
+	}
+	events, cancel := Broadcaster.Subscribe(topic)
+
+	reqCtx.SetContentType("text/event-stream")
+	reqCtx.Response.Header.Set("Cache-Control", "no-cache")
+	reqCtx.Response.Header.Set("Connection", "keep-alive")
+	reqCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		keepAlive := time.NewTicker(keepAliveInterval)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				err := writeEvent(w, ev)
+				if err != nil || ev.Type.Final() {
+					return
+				}
+			case <-keepAlive.C:
+				_, err := w.WriteString(": keep-alive\n\n")
+				if err == nil {
+					err = w.Flush()
+				}
+				if err != nil { // The client is gone
+					return
+				}
+			}
+		}
+	})
+}
+
+// eventsTopic retrieves the topic of the orchestration given by the query args
+func eventsTopic(args *fasthttp.Args) (string, error) {
+	if args.Has("pull_request") {
+		id, err := args.GetUint("pull_request")
+		if err != nil {
+			return "", err
+		}
+		return git.PullRequestTopic(int64(id)), nil
+	}
+	if args.Has("job") {
+		id, err := args.GetUint("job")
+		if err != nil {
+			return "", err
+		}
+		return jobs.Topic(int64(id)), nil
+	}
+	return "", errNoOrchestration
+}
+
+// writeEvent writes the event in the Server-Sent Events format, and flushes it
+func writeEvent(w *bufio.Writer, ev *git.Event) error {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
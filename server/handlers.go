@@ -2,18 +2,22 @@ package server
 
 import (
 	"encoding/json"
-	"os"
 
 	"github.com/jmoiron/sqlx"
 
 	"github.com/sebach1/rtc/config"
 	"github.com/sebach1/rtc/git"
 	"github.com/sebach1/rtc/internal/name"
+	"github.com/sebach1/rtc/jobs"
 	"github.com/valyala/fasthttp"
 )
 
 // Router is a handler which redirects to handlers
 func Router(reqCtx *fasthttp.RequestCtx) {
+	if string(reqCtx.Path()) == "/events" { // Streams, without any db
+		eventsHandler(reqCtx)
+		return
+	}
 	reqCtx.SetContentType("application/json")
 	db := databaseHandler(reqCtx)
 	switch string(reqCtx.Path()) {
@@ -25,17 +29,39 @@ func Router(reqCtx *fasthttp.RequestCtx) {
 		commitHandler(reqCtx, db)
 	case "/orchestrate":
 		orchestrateHandler(reqCtx, db)
+	case "/status":
+		statusHandler(reqCtx, db)
+	case "/log":
+		logHandler(reqCtx, db)
+	case "/revert":
+		revertHandler(reqCtx, db)
+	case "/merge":
+		mergeHandler(reqCtx, db)
+	case "/cherry-pick":
+		cherryPickHandler(reqCtx, db)
+	case "/rebase":
+		rebaseHandler(reqCtx, db)
+	case "/stash/push":
+		stashPushHandler(reqCtx, db)
+	case "/stash/pop":
+		stashPopHandler(reqCtx, db)
+	case "/stash/list":
+		stashListHandler(reqCtx, db)
+	case "/stash/drop":
+		stashDropHandler(reqCtx, db)
+	case "/jobs":
+		jobHandler(reqCtx, db)
+	case "/plan":
+		planHandler(reqCtx, db)
+	case "/results":
+		resultsHandler(reqCtx, db)
 	default:
 		reqCtx.NotFound()
 	}
 }
 
 func databaseHandler(reqCtx *fasthttp.RequestCtx) *sqlx.DB {
-	dataSource := os.Getenv("db")
-	if dataSource == "" {
-		dataSource = config.DefaultDBSrc
-	}
-	db, err := sqlx.Open("postgres", dataSource)
+	db, err := sqlx.Open("postgres", config.DataSource())
 	if err != nil {
 		reqCtx.Error(err.Error(), fasthttp.StatusBadGateway)
 	}
@@ -68,11 +94,12 @@ func addHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
 	body := decoderHandler(reqCtx, validateAdd)
 	var err error
 	respBody := &respBody{}
-	respBody.Change, err = git.Add(reqCtx, db,
-		body.Entity, body.Table, body.Column, body.Branch, body.Value, body.Type, body.Opts,
+	respBody.Change, err = git.Add(reqCtx, db, Project,
+		body.Entity, body.Table, body.Column, body.Branch, body.Schema, body.Value, body.Type, body.Opts,
 	)
 	if err != nil {
 		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
 	}
 	reqCtx.SetStatusCode(fasthttp.StatusAccepted)
 	encoderHandler(reqCtx, respBody)
@@ -107,14 +134,204 @@ func commitHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
 }
 
 func orchestrateHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
-	reqBody := decoderHandler(reqCtx, validateRm)
+	reqBody := decoderHandler(reqCtx, validateOrchestrate)
+	respBody := &respBody{}
+	job, err := jobs.NewJob(reqBody.Branch, reqBody.Schema)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	job.Saga = reqBody.Saga
+	err = jobs.NewQueue(db).Enqueue(reqCtx, job)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	respBody.Job = job
+	reqCtx.SetStatusCode(fasthttp.StatusAccepted)
+	encoderHandler(reqCtx, respBody)
+}
+
+func jobHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateJob)
 	respBody := &respBody{}
 	var err error
-	respBody.Commits, err = git.Comm(reqCtx, db,
-		reqBody.Branch,
+	respBody.Job, err = jobs.JobById(reqCtx, db, reqBody.Job)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusNotFound)
+		return
+	}
+	reqCtx.SetStatusCode(fasthttp.StatusOK)
+	encoderHandler(reqCtx, respBody)
+}
+
+func planHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateOrchestrate)
+	respBody := &respBody{}
+	var err error
+	respBody.Plan, err = git.PlanOrchestration(reqCtx, db, Project, reqBody.Branch, reqBody.Schema, Community)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	reqCtx.SetStatusCode(fasthttp.StatusOK)
+	encoderHandler(reqCtx, respBody)
+}
+
+func resultsHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateResults)
+	respBody := &respBody{}
+	var err error
+	respBody.Results, err = git.ResultsByPullRequest(reqCtx, db, reqBody.PullRequest)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	reqCtx.SetStatusCode(fasthttp.StatusOK)
+	encoderHandler(reqCtx, respBody)
+}
+
+func statusHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateStatus)
+	respBody := &respBody{}
+	var err error
+	respBody.Status, err = git.Status(reqCtx, db, Project, reqBody.Branch)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	reqCtx.SetStatusCode(fasthttp.StatusOK)
+	encoderHandler(reqCtx, respBody)
+}
+
+func logHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateLog)
+	respBody := &respBody{}
+	var err error
+	respBody.History, err = git.Log(reqCtx, db,
+		reqBody.Branch, &git.HistoryFilter{
+			TableName: reqBody.Table,
+			EntityId:  reqBody.Entity,
+			Type:      reqBody.Type,
+			State:     reqBody.State,
+			After:     reqBody.After,
+			Limit:     reqBody.Limit,
+		},
+	)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	reqCtx.SetStatusCode(fasthttp.StatusOK)
+	encoderHandler(reqCtx, respBody)
+}
+
+func mergeHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateMerge)
+	respBody := &respBody{}
+	var err error
+	respBody.Merge, err = git.MergeBranch(reqCtx, db, reqBody.Branch, reqBody.Into)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	if !respBody.Merge.FastForward { // Reports the conflicts
+		reqCtx.SetStatusCode(fasthttp.StatusConflict)
+		encoderHandler(reqCtx, respBody)
+		return
+	}
+	reqCtx.SetStatusCode(fasthttp.StatusOK)
+	encoderHandler(reqCtx, respBody)
+}
+
+func cherryPickHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateMerge)
+	respBody := &respBody{}
+	var err error
+	respBody.Changes, err = git.CherryPick(reqCtx, db, Project, reqBody.Branch, reqBody.Into, reqBody.Commits...)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	reqCtx.SetStatusCode(fasthttp.StatusAccepted)
+	encoderHandler(reqCtx, respBody)
+}
+
+func rebaseHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateRebase)
+	respBody := &respBody{}
+	var err error
+	respBody.Commits, err = git.Rebase(reqCtx, db, reqBody.Branch, reqBody.Onto)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	reqCtx.SetStatusCode(fasthttp.StatusOK)
+	encoderHandler(reqCtx, respBody)
+}
+
+func stashPushHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateStashPush)
+	respBody := &respBody{}
+	var err error
+	respBody.Stash, err = git.StashPush(reqCtx, db, reqBody.Branch, reqBody.Stash)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	reqCtx.SetStatusCode(fasthttp.StatusAccepted)
+	encoderHandler(reqCtx, respBody)
+}
+
+func stashPopHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateStash)
+	respBody := &respBody{}
+	var err error
+	respBody.Stash, respBody.Conflicts, err = git.StashPop(reqCtx, db, Project, reqBody.Branch, reqBody.Stash)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	reqCtx.SetStatusCode(fasthttp.StatusAccepted)
+	encoderHandler(reqCtx, respBody)
+}
+
+func stashListHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateStash)
+	respBody := &respBody{}
+	var err error
+	respBody.Stashes, err = git.StashList(reqCtx, db, reqBody.Branch)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	reqCtx.SetStatusCode(fasthttp.StatusOK)
+	encoderHandler(reqCtx, respBody)
+}
+
+func stashDropHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateStash)
+	respBody := &respBody{}
+	var err error
+	respBody.Stash, err = git.StashDrop(reqCtx, db, reqBody.Branch, reqBody.Stash)
+	if err != nil {
+		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	reqCtx.SetStatusCode(fasthttp.StatusAccepted)
+	encoderHandler(reqCtx, respBody)
+}
+
+func revertHandler(reqCtx *fasthttp.RequestCtx, db *sqlx.DB) {
+	reqBody := decoderHandler(reqCtx, validateRevert)
+	respBody := &respBody{}
+	var err error
+	respBody.Commits, err = git.Revert(reqCtx, db,
+		reqBody.Branch, reqBody.Commits...,
 	)
 	if err != nil {
 		reqCtx.Error(err.Error(), fasthttp.StatusBadRequest)
+		return
 	}
 	reqCtx.SetStatusCode(fasthttp.StatusAccepted)
 	encoderHandler(reqCtx, respBody)
@@ -13,4 +13,21 @@ type reqBody struct {
 	Value  interface{}          `json:"value,omitempty"`
 	Type   integrity.CRUD       `json:"type,omitempty"`
 	Opts   git.Options          `json:"opts,omitempty"`
+
+	State git.CommitState `json:"state,omitempty"`
+	After int64           `json:"after,omitempty"`
+	Limit int             `json:"limit,omitempty"`
+
+	Commits []int64 `json:"commits,omitempty"`
+
+	Into integrity.BranchName `json:"into,omitempty"`
+	Onto integrity.BranchName `json:"onto,omitempty"`
+
+	Stash string `json:"stash,omitempty"`
+
+	Schema integrity.SchemaName `json:"schema,omitempty"`
+	Saga   bool                 `json:"saga,omitempty"`
+	Job    int64                `json:"job,omitempty"`
+
+	PullRequest int64 `json:"pull_request,omitempty"`
 }
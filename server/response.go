@@ -1,12 +1,25 @@
 package server
 
-import "github.com/sebach1/rtc/git"
+import (
+	"github.com/sebach1/rtc/git"
+	"github.com/sebach1/rtc/jobs"
+)
 
 type respBody struct {
 	Commit      *git.Commit
 	Commits     []*git.Commit
 	Change      *git.Change
+	Changes     []*git.Change
 	PullRequest *git.PullRequest
+	History     *git.History
+	Status      *git.BranchStatus
+	Job         *jobs.Job
+	Plan        *git.Plan
+	Results     []*git.Result
+	Merge       *git.MergeReport
+	Conflicts   []*git.Conflict
+	Stash       *git.Stash
+	Stashes     []*git.Stash
 }
 
 // type respBodyErr struct {
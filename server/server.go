@@ -2,3 +2,18 @@
 Package server provides HTTP server related things
 */
 package server
+
+import (
+	"github.com/sebach1/rtc/git"
+	"github.com/sebach1/rtc/schema"
+)
+
+// Project and Community are the ones the branches are planned through. See git.PlanOrchestration
+// The Project also validates the changes added onto the branches, and its pending work. See git.Add and git.Status
+var (
+	Project   *schema.Planisphere
+	Community *git.Community
+)
+
+// Broadcaster is the one the orchestrations publish its progress onto, in order to be streamed
+var Broadcaster *git.Broadcaster
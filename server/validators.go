@@ -29,3 +29,85 @@ func validateAdd(body *reqBody) error {
 	}
 	return nil
 }
+
+func validateStatus(body *reqBody) error {
+	if body.Branch == "" {
+		return errNoBranch
+	}
+	return nil
+}
+
+func validateLog(body *reqBody) error {
+	if body.Branch == "" {
+		return errNoBranch
+	}
+	return nil
+}
+
+func validateRevert(body *reqBody) error {
+	if body.Branch == "" {
+		return errNoBranch
+	}
+	if len(body.Commits) == 0 {
+		return errNoCommits
+	}
+	return nil
+}
+
+func validateMerge(body *reqBody) error {
+	if body.Branch == "" {
+		return errNoBranch
+	}
+	if body.Into == "" {
+		return errNoTarget
+	}
+	return nil
+}
+
+func validateRebase(body *reqBody) error {
+	if body.Branch == "" {
+		return errNoBranch
+	}
+	if body.Onto == "" {
+		return errNoOnto
+	}
+	return nil
+}
+
+func validateStash(body *reqBody) error {
+	if body.Branch == "" {
+		return errNoBranch
+	}
+	return nil
+}
+
+func validateStashPush(body *reqBody) error {
+	if body.Branch == "" {
+		return errNoBranch
+	}
+	if body.Stash == "" {
+		return errNoStash
+	}
+	return nil
+}
+
+func validateOrchestrate(body *reqBody) error {
+	if body.Branch == "" {
+		return errNoBranch
+	}
+	return nil
+}
+
+func validateResults(body *reqBody) error {
+	if body.PullRequest == 0 {
+		return errNoPullRequest
+	}
+	return nil
+}
+
+func validateJob(body *reqBody) error {
+	if body.Job == 0 {
+		return errNoJob
+	}
+	return nil
+}